@@ -0,0 +1,202 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Bulk UPDATE from a VALUES list
+// e.g.
+// UPDATE t SET a = v.a FROM (VALUES (?, ?), (?, ?)) AS v(id, a) WHERE t.id = v.id
+//
+// the join form for SQL Server
+// UPDATE u SET a = v.a FROM t u JOIN (VALUES (?, ?), (?, ?)) AS v(id, a) ON u.id = v.id
+//
+// and the CASE form for dialects without UPDATE ... FROM a VALUES list (MySQL, Oracle, SQLite)
+// UPDATE t SET a = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? ELSE a END WHERE id IN (?,?)
+
+// valuesFrom holds rows of the bulk update.
+type valuesFrom struct {
+	alias   string
+	keyCols []string
+	rows    []map[string]any
+}
+
+// columns returns the value columns of the rows and checks that all rows have the same columns.
+func (v *valuesFrom) columns() ([]string, error) {
+	if len(v.rows) == 0 {
+		return nil, errors.New("update from values must have at least one row")
+	}
+	if len(v.keyCols) == 0 {
+		return nil, errors.New("update from values must have at least one key column")
+	}
+
+	var valueCols []string
+	for col := range v.rows[0] {
+		if !slices.Contains(v.keyCols, col) {
+			valueCols = append(valueCols, col)
+		}
+	}
+	sort.Strings(valueCols)
+
+	if len(valueCols) == 0 {
+		return nil, errors.New("update from values must have at least one value column")
+	}
+
+	width := len(v.keyCols) + len(valueCols)
+	for i, row := range v.rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("update from values row %d has %d columns, expected %d", i, len(row), width)
+		}
+		for _, col := range v.keyCols {
+			if _, ok := row[col]; !ok {
+				return nil, fmt.Errorf("update from values row %d has no key column %s", i, col)
+			}
+		}
+		for _, col := range valueCols {
+			if _, ok := row[col]; !ok {
+				return nil, fmt.Errorf("update from values row %d has no column %s", i, col)
+			}
+		}
+	}
+
+	return valueCols, nil
+}
+
+// keyEq returns the condition matching the key columns of the row.
+func (v *valuesFrom) keyEq(row map[string]any) Eq {
+	eq := make(Eq, len(v.keyCols))
+	for _, col := range v.keyCols {
+		eq[col] = row[col]
+	}
+	return eq
+}
+
+// tableRef returns the name to reference the updated table, e.g. "u" for "users AS u".
+func tableRef(table string) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return table
+	}
+	return fields[len(fields)-1]
+}
+
+// expandValuesFrom returns a copy of the update data with SET, FROM and WHERE
+// clauses generated from the values rows.
+func (d *updateData) expandValuesFrom(dialect Dialect) (*updateData, error) {
+	valueCols, err := d.ValuesFrom.columns()
+	if err != nil {
+		return nil, err
+	}
+
+	res := *d
+	res.SetClauses = slices.Clip(d.SetClauses)
+	res.WhereParts = slices.Clip(d.WhereParts)
+
+	switch dialect { //nolint:exhaustive // other dialects support UPDATE ... FROM
+	case DialectMySQL, DialectOracle, DialectSQLite:
+		// SQLite has no column aliases of VALUES list: "(VALUES ...) AS v(id, a)"
+		res.expandValuesCase(valueCols)
+		return &res, nil
+	}
+
+	if d.From != nil {
+		return nil, errors.New("update from values cannot be used with From clause")
+	}
+
+	v := d.ValuesFrom
+	cols := append(slices.Clone(v.keyCols), valueCols...)
+	rows := make([][]any, len(v.rows))
+	for i, row := range v.rows {
+		rows[i] = make([]any, len(cols))
+		for j, col := range cols {
+			rows[i][j] = row[col]
+		}
+	}
+
+	values := Values(rows).As(v.alias, cols...)
+	if dialect == DialectPostgres || dialect == DialectUndefined {
+		values = values.InferTypes()
+	}
+
+	for _, col := range valueCols {
+		res.SetClauses = append(res.SetClauses, setClause{column: col, value: Expr(v.alias + "." + col)})
+	}
+
+	target := tableRef(d.Table)
+	keyConds := make([]string, len(v.keyCols))
+	for i, col := range v.keyCols {
+		keyConds[i] = fmt.Sprintf("%s.%s = %s.%s", target, col, v.alias, col)
+	}
+
+	if dialect == DialectSQLServer {
+		// SQL Server does not allow the alias of the target table in UPDATE clause,
+		// so the target is referenced by the alias through the joins of FROM clause
+		join := newJoinPart("JOIN ? ON "+strings.Join(keyConds, " AND "), valuesTable{values: values})
+		res.Joins = append([]Sqlizer{join}, d.Joins...)
+		return &res, nil
+	}
+
+	res.From = valuesTable{values: values}
+	for _, cond := range keyConds {
+		res.WhereParts = append(res.WhereParts, Expr(cond))
+	}
+
+	return &res, nil
+}
+
+// expandValuesCase generates CASE based SET clauses for dialects without UPDATE ... FROM.
+func (d *updateData) expandValuesCase(valueCols []string) {
+	v := d.ValuesFrom
+
+	for _, col := range valueCols {
+		c := Case()
+		for _, row := range v.rows {
+			// values are wrapped to avoid CAST of THEN values
+			c = c.When(v.keyEq(row), Expr("?", row[col]))
+		}
+		d.SetClauses = append(d.SetClauses, setClause{column: col, value: c.Else(Expr(col))})
+	}
+
+	if len(v.keyCols) == 1 {
+		keys := make([]any, len(v.rows))
+		for i, row := range v.rows {
+			keys[i] = row[v.keyCols[0]]
+		}
+		d.WhereParts = append(d.WhereParts, Eq{v.keyCols[0]: keys})
+		return
+	}
+
+	keys := make(Or, len(v.rows))
+	for i, row := range v.rows {
+		keys[i] = v.keyEq(row)
+	}
+	d.WhereParts = append(d.WhereParts, keys)
+}
+
+// FromValues updates many rows with different values in one statement.
+// Each row must contain the key columns, which are used to match the updated rows,
+// and the same set of value columns, which are set.
+//
+// For PostgreSQL it renders
+//
+//	UPDATE t SET a = v.a FROM (VALUES (?, ?), (?, ?)) AS v(id, a) WHERE t.id = v.id
+//
+// for SQL Server the VALUES list is joined to the target table
+//
+//	UPDATE t SET a = v.a FROM t JOIN (VALUES (?, ?), (?, ?)) AS v(id, a) ON t.id = v.id
+//
+// and for MySQL and Oracle, which have no UPDATE ... FROM, and SQLite, which has no column
+// aliases of VALUES list, the CASE form
+//
+//	UPDATE t SET a = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? ELSE a END WHERE id IN (?,?)
+//
+// The column types of the VALUES list are inferred for PostgreSQL, see ValuesBuilder.InferTypes.
+func (b UpdateBuilder) FromValues(alias string, keyCols []string, rows []map[string]any) UpdateBuilder {
+	return builder.Set(b, "ValuesFrom", &valuesFrom{alias: alias, keyCols: keyCols, rows: rows}).(UpdateBuilder)
+}
@@ -0,0 +1,107 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateFromValues(t *testing.T) {
+	t.Parallel()
+	rows := []map[string]any{
+		{"id": 1, "name": "moe", "age": 30},
+		{"id": 2, "name": "larry", "age": 40},
+	}
+
+	sql, args, err := Update("users").
+		FromValues("v", []string{"id"}, rows).
+		Where("users.active = ?", true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET age = v.age, name = v.name "+
+		"FROM (VALUES (CAST($1 AS bigint), CAST($2 AS bigint), CAST($3 AS text)), ($4, $5, $6)) AS v(id, age, name) "+
+		"WHERE users.active = $7 AND users.id = v.id", sql)
+	assert.Equal(t, []any{1, 30, "moe", 2, 40, "larry", true}, args)
+
+	sql, _, err = Update("users u").
+		Set("updated_at", Expr("now()")).
+		FromValues("v", []string{"id"}, rows).
+		Dialect(DialectSQLServer).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE u SET updated_at = now(), age = v.age, name = v.name "+
+		"FROM users u JOIN (VALUES (?, ?, ?), (?, ?, ?)) AS v(id, age, name) ON u.id = v.id", sql)
+
+	sql, args, err = Update("users").
+		FromValues("v", []string{"id"}, rows).
+		Join("teams t ON t.id = users.team_id").
+		Where("t.active = ?", true).
+		PlaceholderFormat(AtP).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET age = v.age, name = v.name "+
+		"FROM users JOIN (VALUES (@p1, @p2, @p3), (@p4, @p5, @p6)) AS v(id, age, name) ON users.id = v.id "+
+		"JOIN teams t ON t.id = users.team_id WHERE t.active = @p7", sql)
+	assert.Equal(t, []any{1, 30, "moe", 2, 40, "larry", true}, args)
+}
+
+func TestUpdateFromValuesCase(t *testing.T) {
+	t.Parallel()
+	rows := []map[string]any{
+		{"id": 1, "name": "moe"},
+		{"id": 2, "name": "larry"},
+	}
+
+	sql, args, err := Update("users").FromValues("v", []string{"id"}, rows).Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? ELSE name END "+
+		"WHERE id IN (?,?)", sql)
+	assert.Equal(t, []any{1, "moe", 2, "larry", 1, 2}, args)
+
+	sql, _, err = Update("users").FromValues("v", []string{"id"}, rows).Dialect(DialectSQLite).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? ELSE name END "+
+		"WHERE id IN (?,?)", sql)
+
+	rows = []map[string]any{
+		{"a": 1, "b": 2, "qty": 10},
+		{"a": 1, "b": 3, "qty": 20},
+	}
+	sql, args, err = Update("stock").FromValues("v", []string{"a", "b"}, rows).PlaceholderFormat(Colon).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE stock SET qty = CASE WHEN a = :1 AND b = :2 THEN :3 "+
+		"WHEN a = :4 AND b = :5 THEN :6 ELSE qty END "+
+		"WHERE (a = :7 AND b = :8 OR a = :9 AND b = :10)", sql)
+	assert.Equal(t, []any{1, 2, 10, 1, 3, 20, 1, 2, 1, 3}, args)
+}
+
+func TestUpdateFromValuesErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Update("users").FromValues("v", []string{"id"}, nil).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Update("users").FromValues("v", nil, []map[string]any{{"id": 1, "name": "moe"}}).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Update("users").FromValues("v", []string{"id"}, []map[string]any{{"id": 1}}).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Update("users").FromValues("v", []string{"id"}, []map[string]any{
+		{"id": 1, "name": "moe"},
+		{"id": 2, "age": 40},
+	}).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Update("users").FromValues("v", []string{"id"}, []map[string]any{
+		{"id": 1, "name": "moe"},
+		{"name": "larry", "age": 40},
+	}).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Update("users").From("other").
+		FromValues("v", []string{"id"}, []map[string]any{{"id": 1, "name": "moe"}}).
+		ToSql()
+	require.Error(t, err)
+}
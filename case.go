@@ -100,6 +100,10 @@ func sqlTypeNameHelper(t reflect.Type) (string, error) {
 			return "timestamp with time zone", nil
 		}
 	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// []byte is a binary value, not an array of numbers
+			return "bytea", nil
+		}
 		sqlType, err := sqlTypeNameHelper(t.Elem())
 		if err != nil {
 			return "", err
@@ -221,6 +221,8 @@ func TestSqlTypeNameHelper(t *testing.T) {
 		{"String", reflect.TypeOf(string("test")), "text", false},
 		{"Time", reflect.TypeOf(time.Time{}), "timestamp with time zone", false},
 		{"Slice", reflect.TypeOf([]int{1, 2, 3}), "bigint[]", false},
+		{"Bytes", reflect.TypeOf([]byte("test")), "bytea", false},
+		{"BytesSlice", reflect.TypeOf([][]byte{}), "bytea[]", false},
 		{"Unsupported", reflect.TypeOf(struct{}{}), "", true},
 	}
 
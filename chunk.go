@@ -0,0 +1,149 @@
+package squirrel
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/lann/builder"
+)
+
+// Maximum number of bind parameters in a single statement.
+const (
+	maxParamsPostgres  = 65535
+	maxParamsMySQL     = 65535
+	maxParamsSQLite    = 32766 // SQLITE_MAX_VARIABLE_NUMBER since 3.32.0
+	maxParamsSQLServer = 2100
+	maxParamsOracle    = 65535
+)
+
+// dialectMaxParams returns the bind parameters limit of the dialect.
+func dialectMaxParams(dialect Dialect) int {
+	switch dialect {
+	case DialectMySQL:
+		return maxParamsMySQL
+	case DialectSQLite:
+		return maxParamsSQLite
+	case DialectSQLServer:
+		return maxParamsSQLServer
+	case DialectOracle:
+		return maxParamsOracle
+	case DialectPostgres, DialectUndefined:
+	}
+	return maxParamsPostgres
+}
+
+// rowParams returns the number of bind parameters of the row.
+func rowParams(row []any) int {
+	n := 0
+	for _, val := range row {
+		vs, ok := val.(Sqlizer)
+		if !ok {
+			n++
+			continue
+		}
+		_, vargs, err := nestedToSql(vs)
+		if err != nil {
+			// the error is returned by ToSql of the chunk
+			n++
+			continue
+		}
+		n += len(vargs)
+	}
+	return n
+}
+
+// chunker splits rows into chunks within the bind parameters budget.
+type chunker struct {
+	template InsertBuilder
+	budget   int
+	overhead int // parameters of the statement besides the rows
+	resolved bool
+}
+
+func newChunker(b InsertBuilder, maxParams int) *chunker {
+	data := builder.GetStruct(b).(insertData)
+	if maxParams <= 0 {
+		maxParams = dialectMaxParams(resolveDialect(data.Dialect, data.PlaceholderFormat))
+	}
+	return &chunker{
+		template: builder.Delete(b, "Values").(InsertBuilder),
+		budget:   maxParams,
+		overhead: 0,
+		resolved: false,
+	}
+}
+
+// resolveOverhead counts parameters of prefixes, suffixes, conflict clause and so on
+// by building the statement with the first row.
+func (c *chunker) resolveOverhead(row []any) {
+	if c.resolved {
+		return
+	}
+	c.resolved = true
+
+	data := builder.GetStruct(c.template.Values(row...)).(insertData)
+	_, args, err := data.toSqlRaw()
+	if err != nil {
+		return
+	}
+	c.overhead = max(len(args)-rowParams(row), 0)
+}
+
+// fits returns true if the row with the given params can be added to the chunk.
+func (c *chunker) fits(chunkParams, params, chunkRows int) bool {
+	return chunkRows == 0 || c.overhead+chunkParams+params <= c.budget
+}
+
+func (c *chunker) chunk(rows [][]any) InsertBuilder {
+	return builder.Set(c.template, "Values", rows).(InsertBuilder)
+}
+
+// Chunks splits the multi-row insert query into several queries, so that each
+// of them has no more than maxParams bind parameters. If maxParams is not
+// positive, the limit of the dialect is used (e.g. 65535 for PostgreSQL, 2100 for SQL Server).
+//
+// A row which exceeds the limit alone is placed into a separate query.
+// The query is returned as is if it uses Select instead of Values, or Unnest mode.
+func (b InsertBuilder) Chunks(maxParams int) []InsertBuilder {
+	data := builder.GetStruct(b).(insertData)
+	if data.Select != nil || data.Unnest || len(data.Values) == 0 {
+		return []InsertBuilder{b}
+	}
+
+	var chunks []InsertBuilder
+	for chunk := range b.ChunksSeq(slices.Values(data.Values), maxParams) {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// ChunksSeq works like Chunks, but takes the rows from the sequence, so that
+// huge imports can be streamed without keeping all rows in memory.
+// The query is used as a template, its own Values are ignored.
+func (b InsertBuilder) ChunksSeq(rows iter.Seq[[]any], maxParams int) iter.Seq[InsertBuilder] {
+	return func(yield func(InsertBuilder) bool) {
+		c := newChunker(b, maxParams)
+
+		var chunkRows [][]any
+		chunkParams := 0
+		for row := range rows {
+			c.resolveOverhead(row)
+
+			params := rowParams(row)
+			if !c.fits(chunkParams, params, len(chunkRows)) {
+				if !yield(c.chunk(chunkRows)) {
+					return
+				}
+				chunkRows = nil
+				chunkParams = 0
+			}
+
+			chunkRows = append(chunkRows, row)
+			chunkParams += params
+		}
+
+		if len(chunkRows) > 0 {
+			yield(c.chunk(chunkRows))
+		}
+	}
+}
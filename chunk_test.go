@@ -0,0 +1,94 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertChunks(t *testing.T) {
+	t.Parallel()
+	b := Insert("t").Columns("a", "b")
+	for i := range 5 {
+		b = b.Values(i, i*10)
+	}
+
+	chunks := b.Chunks(4)
+	require.Len(t, chunks, 3)
+
+	sql, args, err := chunks[0].ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b) VALUES (?,?),(?,?)", sql)
+	assert.Equal(t, []any{0, 0, 1, 10}, args)
+
+	sql, args, err = chunks[2].ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b) VALUES (?,?)", sql)
+	assert.Equal(t, []any{4, 40}, args)
+
+	assert.Len(t, b.Chunks(0), 1)
+	assert.Len(t, b.Chunks(1), 5)
+}
+
+func TestInsertChunksOverhead(t *testing.T) {
+	t.Parallel()
+	b := Insert("t").Columns("a", "b").
+		Values(1, Expr("now()")).
+		Values(2, Expr("? + ?", 1, 2)).
+		Values(3, 4).
+		OnConflict("a").DoUpdate().Set("b", Expr("t.b + ?", 1)).End().
+		PlaceholderFormat(Dollar)
+
+	chunks := b.Chunks(4)
+	require.Len(t, chunks, 3)
+
+	sql, args, err := chunks[0].ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b) VALUES ($1,now()) ON CONFLICT (a) DO UPDATE SET b = t.b + $2", sql)
+	assert.Equal(t, []any{1, 1}, args)
+
+	sql, args, err = chunks[2].ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b) VALUES ($1,$2) ON CONFLICT (a) DO UPDATE SET b = t.b + $3", sql)
+	assert.Equal(t, []any{3, 4, 1}, args)
+}
+
+func TestInsertChunksDialectBudget(t *testing.T) {
+	t.Parallel()
+	row := make([]any, 1000)
+	b := Insert("t").Values(row...).Values(row...).Values(row...)
+
+	assert.Len(t, b.Chunks(0), 1)
+	assert.Len(t, b.PlaceholderFormat(AtP).Chunks(0), 2)
+	assert.Len(t, b.Dialect(DialectSQLServer).Chunks(0), 2)
+
+	sel := Insert("t").Select(Select("a").From("s"))
+	assert.Equal(t, []InsertBuilder{sel}, sel.Chunks(1))
+}
+
+func TestInsertChunksSeq(t *testing.T) {
+	t.Parallel()
+	rows := func(yield func([]any) bool) {
+		for i := range 7 {
+			if !yield([]any{i}) {
+				return
+			}
+		}
+	}
+
+	var sizes []int
+	for chunk := range Insert("t").Columns("a").ChunksSeq(rows, 3) {
+		_, args, err := chunk.ToSql()
+		require.NoError(t, err)
+		sizes = append(sizes, len(args))
+	}
+	assert.Equal(t, []int{3, 3, 1}, sizes)
+
+	count := 0
+	for range Insert("t").Columns("a").ChunksSeq(rows, 3) {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,369 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// ON CONFLICT clause of INSERT statements
+// e.g.
+// INSERT INTO t (id, name) VALUES (?, ?)
+// ON CONFLICT (id) WHERE deleted_at IS NULL
+// DO UPDATE SET name = EXCLUDED.name WHERE t.locked = false
+//
+// and its MySQL counterpart
+// INSERT INTO t (id, name) VALUES (?, ?) AS new
+// ON DUPLICATE KEY UPDATE name = new.name
+
+type conflictAction int
+
+const (
+	conflictActionUndefined conflictAction = iota
+	conflictActionNothing
+	conflictActionUpdate
+)
+
+// excludedValue references the value proposed for insertion (EXCLUDED.column).
+type excludedValue struct {
+	column string
+}
+
+func (e excludedValue) ToSql() (sql string, args []any, err error) {
+	return "EXCLUDED." + e.column, nil, nil
+}
+
+func (d *insertData) hasConflict() bool {
+	return d.ConflictAction != conflictActionUndefined ||
+		len(d.ConflictTarget) > 0 || d.ConflictConstraint != "" || len(d.ConflictTargetWhere) > 0
+}
+
+func (d *insertData) hasConflictTarget() bool {
+	return len(d.ConflictTarget) > 0 || d.ConflictConstraint != ""
+}
+
+// mysqlConflictFeature returns the name of the used MySQL-only conflict feature.
+func (d *insertData) mysqlConflictFeature() string {
+	switch {
+	case d.RowAlias != "":
+		return "row alias"
+	case d.DuplicateKey && d.ConflictAction == conflictActionNothing:
+		return "INSERT IGNORE"
+	case d.DuplicateKey:
+		return "ON DUPLICATE KEY UPDATE"
+	}
+	return ""
+}
+
+// conflictDialect returns the dialect used to render the conflict clause and
+// checks that the dialect supports the clause.
+func (d *insertData) conflictDialect() (Dialect, error) {
+	dialect := resolveDialect(d.Dialect, d.PlaceholderFormat)
+
+	if feature := d.mysqlConflictFeature(); feature != "" {
+		if dialect != DialectMySQL && dialect != DialectUndefined {
+			return dialect, errUnsupported(feature, dialect)
+		}
+		dialect = DialectMySQL
+	}
+
+	if !d.hasConflict() && d.RowAlias == "" {
+		return dialect, nil
+	}
+
+	switch dialect { //nolint:exhaustive // other dialects support ON CONFLICT
+	case DialectSQLServer, DialectOracle:
+		return dialect, errUnsupported("ON CONFLICT", dialect)
+	case DialectMySQL:
+		return dialect, d.checkMySQLConflict()
+	}
+
+	return dialect, nil
+}
+
+func (d *insertData) checkMySQLConflict() error {
+	if _, ok := d.PlaceholderFormat.(questionFormat); !ok && d.PlaceholderFormat != nil {
+		return errors.New("mysql upsert requires Question placeholder format")
+	}
+	if d.StatementKeyword != "" {
+		return fmt.Errorf("mysql upsert cannot be used with %s statement", d.StatementKeyword)
+	}
+	if d.ConflictConstraint != "" {
+		return errUnsupported("ON CONFLICT ON CONSTRAINT", DialectMySQL)
+	}
+	if len(d.ConflictTargetWhere) > 0 {
+		return errUnsupported("ON CONFLICT target predicate", DialectMySQL)
+	}
+	if len(d.ConflictWhereParts) > 0 {
+		return errUnsupported("ON CONFLICT DO UPDATE WHERE", DialectMySQL)
+	}
+	if d.RowAlias != "" && d.Select != nil {
+		return errors.New("row alias cannot be used with insert select")
+	}
+	return nil
+}
+
+// conflictSetClauseSQL builds an assignment of DO UPDATE SET or ON DUPLICATE KEY UPDATE clause.
+func (d *insertData) conflictSetClauseSQL(sc setClause, dialect Dialect) (sql string, args []any, err error) {
+	if ex, ok := sc.value.(excludedValue); ok && dialect == DialectMySQL {
+		if d.RowAlias != "" {
+			return fmt.Sprintf("%s = %s.%s", sc.column, d.RowAlias, ex.column), nil, nil
+		}
+		return fmt.Sprintf("%s = VALUES(%s)", sc.column, ex.column), nil, nil
+	}
+
+	return buildSetClauseSQL(sc, dialect)
+}
+
+func (d *insertData) writeConflictSetClauses(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	if len(d.ConflictSetClauses) == 0 {
+		return nil, errors.New("on conflict do update must have at least one Set clause")
+	}
+
+	setSqls := make([]string, len(d.ConflictSetClauses))
+	for i, sc := range d.ConflictSetClauses {
+		setSql, setArgs, err := d.conflictSetClauseSQL(sc, dialect)
+		if err != nil {
+			return nil, err
+		}
+		setSqls[i] = setSql
+		args = append(args, setArgs...)
+	}
+	_, _ = sql.WriteString(strings.Join(setSqls, ", "))
+
+	return args, nil
+}
+
+func (d *insertData) writeConflictTarget(sql *bytes.Buffer, args []any) ([]any, error) {
+	if d.ConflictConstraint != "" {
+		if len(d.ConflictTargetWhere) > 0 {
+			return nil, errors.New("on conflict target predicate cannot be used with ON CONSTRAINT")
+		}
+		_, _ = sql.WriteString(" ON CONSTRAINT ")
+		_, _ = sql.WriteString(d.ConflictConstraint)
+		return args, nil
+	}
+
+	if len(d.ConflictTarget) == 0 {
+		if len(d.ConflictTargetWhere) > 0 {
+			return nil, errors.New("on conflict target predicate requires conflict target columns")
+		}
+		return args, nil
+	}
+
+	_, _ = sql.WriteString(" (")
+	_, _ = sql.WriteString(strings.Join(d.ConflictTarget, ", "))
+	_, _ = sql.WriteString(")")
+
+	if len(d.ConflictTargetWhere) == 0 {
+		return args, nil
+	}
+
+	_, _ = sql.WriteString(" WHERE ")
+	return appendToSql(d.ConflictTargetWhere, sql, " AND ", args)
+}
+
+func (d *insertData) writeConflictUpdate(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	if !d.hasConflictTarget() {
+		return nil, errors.New("on conflict do update requires a conflict target")
+	}
+
+	_, _ = sql.WriteString(" DO UPDATE SET ")
+
+	args, err := d.writeConflictSetClauses(sql, dialect, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.ConflictWhereParts) == 0 {
+		return args, nil
+	}
+
+	_, _ = sql.WriteString(" WHERE ")
+	return appendToSql(d.ConflictWhereParts, sql, " AND ", args)
+}
+
+// writeDuplicateKeyUpdate writes MySQL variant of the conflict clause.
+// DO NOTHING action is rendered as INSERT IGNORE by writeInsertClause.
+func (d *insertData) writeDuplicateKeyUpdate(sql *bytes.Buffer, args []any) ([]any, error) {
+	switch d.ConflictAction {
+	case conflictActionNothing:
+		return args, nil
+	case conflictActionUpdate:
+		_, _ = sql.WriteString(" ON DUPLICATE KEY UPDATE ")
+		return d.writeConflictSetClauses(sql, DialectMySQL, args)
+	case conflictActionUndefined:
+	}
+
+	return nil, errors.New("on duplicate key clause must have IGNORE or UPDATE action")
+}
+
+func (d *insertData) writeOnConflict(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	if !d.hasConflict() {
+		return args, nil
+	}
+
+	if dialect == DialectMySQL {
+		return d.writeDuplicateKeyUpdate(sql, args)
+	}
+
+	_, _ = sql.WriteString(" ON CONFLICT")
+
+	args, err := d.writeConflictTarget(sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	switch d.ConflictAction {
+	case conflictActionNothing:
+		_, _ = sql.WriteString(" DO NOTHING")
+		return args, nil
+	case conflictActionUpdate:
+		return d.writeConflictUpdate(sql, dialect, args)
+	case conflictActionUndefined:
+	}
+
+	return nil, errors.New("on conflict clause must have DO NOTHING or DO UPDATE action")
+}
+
+// Builder
+
+// OnConflict starts ON CONFLICT clause with the given conflict target columns.
+// Columns can be omitted for DO NOTHING action.
+// Finish the clause with OnConflictBuilder.DoNothing or OnConflictBuilder.DoUpdate.
+//
+// For the MySQL dialect the clause is rendered as INSERT IGNORE or
+// ON DUPLICATE KEY UPDATE, and the target columns are ignored, because MySQL
+// checks all unique keys of the table.
+func (b InsertBuilder) OnConflict(columns ...string) OnConflictBuilder {
+	b = builder.Delete(b, "ConflictConstraint").(InsertBuilder)
+	b = builder.Delete(b, "DuplicateKey").(InsertBuilder)
+	return OnConflictBuilder(builder.Set(b, "ConflictTarget", columns).(InsertBuilder))
+}
+
+// OnConstraint starts "ON CONFLICT ON CONSTRAINT name" clause.
+// Finish the clause with OnConflictBuilder.DoNothing or OnConflictBuilder.DoUpdate.
+func (b InsertBuilder) OnConstraint(name string) OnConflictBuilder {
+	b = builder.Delete(b, "ConflictTarget").(InsertBuilder)
+	b = builder.Delete(b, "DuplicateKey").(InsertBuilder)
+	return OnConflictBuilder(builder.Set(b, "ConflictConstraint", name).(InsertBuilder))
+}
+
+// OnDuplicateKeyUpdate starts MySQL "ON DUPLICATE KEY UPDATE" clause.
+// Add assignments with OnConflictUpdateBuilder.Set and similar methods,
+// SetExcluded is rendered as "column = VALUES(column)" or "column = alias.column"
+// if RowAlias is set.
+//
+// It returns an error on ToSql if the dialect is not MySQL or undefined,
+// or if the placeholder format is not Question.
+func (b InsertBuilder) OnDuplicateKeyUpdate() OnConflictUpdateBuilder {
+	b = builder.Delete(b, "ConflictConstraint").(InsertBuilder)
+	b = builder.Delete(b, "ConflictTarget").(InsertBuilder)
+	b = builder.Set(b, "DuplicateKey", true).(InsertBuilder)
+	return OnConflictUpdateBuilder(builder.Set(b, "ConflictAction", conflictActionUpdate).(InsertBuilder))
+}
+
+// Ignore makes MySQL "INSERT IGNORE" statement.
+//
+// See OnDuplicateKeyUpdate for the supported dialects.
+func (b InsertBuilder) Ignore() InsertBuilder {
+	b = builder.Delete(b, "ConflictConstraint").(InsertBuilder)
+	b = builder.Delete(b, "ConflictTarget").(InsertBuilder)
+	b = builder.Set(b, "DuplicateKey", true).(InsertBuilder)
+	return builder.Set(b, "ConflictAction", conflictActionNothing).(InsertBuilder)
+}
+
+// RowAlias sets MySQL 8.0.19+ row alias "VALUES (...) AS alias", which can be
+// referenced in ON DUPLICATE KEY UPDATE clause instead of VALUES() function.
+//
+// See OnDuplicateKeyUpdate for the supported dialects.
+func (b InsertBuilder) RowAlias(alias string) InsertBuilder {
+	return builder.Set(b, "RowAlias", alias).(InsertBuilder)
+}
+
+// OnConflictBuilder builds the conflict target of ON CONFLICT clause.
+type OnConflictBuilder builder.Builder
+
+// Where adds the index predicate of the conflict target, which allows to
+// use partial unique indexes. Expressions are ANDed together.
+//
+// See SelectBuilder.Where for the supported types of pred.
+func (b OnConflictBuilder) Where(pred any, args ...any) OnConflictBuilder {
+	return builder.Append(b, "ConflictTargetWhere", newWherePart(pred, args...)).(OnConflictBuilder)
+}
+
+// DoNothing finishes ON CONFLICT clause with DO NOTHING action.
+func (b OnConflictBuilder) DoNothing() InsertBuilder {
+	return InsertBuilder(builder.Set(b, "ConflictAction", conflictActionNothing).(OnConflictBuilder))
+}
+
+// DoUpdate sets DO UPDATE action of ON CONFLICT clause.
+// Add assignments with OnConflictUpdateBuilder.Set and similar methods.
+func (b OnConflictBuilder) DoUpdate() OnConflictUpdateBuilder {
+	return OnConflictUpdateBuilder(builder.Set(b, "ConflictAction", conflictActionUpdate).(OnConflictBuilder))
+}
+
+// OnConflictUpdateBuilder builds DO UPDATE action of ON CONFLICT clause.
+type OnConflictUpdateBuilder builder.Builder
+
+// Set adds "column = value" assignment to DO UPDATE SET clause.
+//
+// See UpdateBuilder.Set for more information.
+func (b OnConflictUpdateBuilder) Set(column string, value any) OnConflictUpdateBuilder {
+	return builder.Append(b, "ConflictSetClauses", setClause{column: column, value: value}).(OnConflictUpdateBuilder)
+}
+
+// SetMap is a convenience method which calls .Set for each key/value pair in clauses.
+func (b OnConflictUpdateBuilder) SetMap(clauses map[string]any) OnConflictUpdateBuilder {
+	keys := make([]string, 0, len(clauses))
+	for key := range clauses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b = b.Set(key, clauses[key])
+	}
+	return b
+}
+
+// SetExcluded adds "column = EXCLUDED.column" assignments, i.e. overwrites the
+// columns with the values proposed for insertion.
+func (b OnConflictUpdateBuilder) SetExcluded(columns ...string) OnConflictUpdateBuilder {
+	for _, column := range columns {
+		b = b.Set(column, excludedValue{column: column})
+	}
+	return b
+}
+
+// Where adds WHERE expressions to DO UPDATE action. Rows that don't match
+// the condition are not updated.
+//
+// See SelectBuilder.Where for the supported types of pred.
+func (b OnConflictUpdateBuilder) Where(pred any, args ...any) OnConflictUpdateBuilder {
+	return builder.Append(b, "ConflictWhereParts", newWherePart(pred, args...)).(OnConflictUpdateBuilder)
+}
+
+// End finishes DO UPDATE action and returns the InsertBuilder to continue building the query.
+func (b OnConflictUpdateBuilder) End() InsertBuilder {
+	return InsertBuilder(b)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b OnConflictUpdateBuilder) ToSql() (sql string, args []any, err error) {
+	return b.End().ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b OnConflictUpdateBuilder) MustSql() (sql string, args []any) {
+	return b.End().MustSql()
+}
+
+// toSqlRaw builds SQL with raw placeholders ("?") without applying PlaceholderFormat.
+func (b OnConflictUpdateBuilder) toSqlRaw() (sql string, args []any, err error) {
+	return b.End().toSqlRaw()
+}
@@ -0,0 +1,164 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertOnConflictDoNothing(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("users").Columns("id", "name").Values(1, "moe").
+		OnConflict().DoNothing().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) ON CONFLICT DO NOTHING", sql)
+	assert.Equal(t, []any{1, "moe"}, args)
+
+	sql, _, err = Insert("users").Columns("id", "name").Values(1, "moe").
+		OnConstraint("users_pkey").DoNothing().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) ON CONFLICT ON CONSTRAINT users_pkey DO NOTHING", sql)
+}
+
+func TestInsertOnConflictDoUpdate(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("users").Columns("id", "name", "visits").Values(1, "moe", 1).
+		OnConflict("id").Where("deleted_at IS NULL").
+		DoUpdate().
+		SetExcluded("name").
+		Set("visits", Expr("users.visits + ?", 1)).
+		Where("users.locked = ?", false).
+		End().
+		Suffix("RETURNING id").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+
+	expectedSQL := "INSERT INTO users (id,name,visits) VALUES ($1,$2,$3) " +
+		"ON CONFLICT (id) WHERE deleted_at IS NULL " +
+		"DO UPDATE SET name = EXCLUDED.name, visits = users.visits + $4 WHERE users.locked = $5 " +
+		"RETURNING id"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []any{1, "moe", 1, 1, false}, args)
+
+	sql, args, err = Insert("users").Columns("id", "name").Values(1, "moe").
+		OnConstraint("users_pkey").DoUpdate().SetMap(map[string]any{"name": "larry", "age": 2}).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) "+
+		"ON CONFLICT ON CONSTRAINT users_pkey DO UPDATE SET age = ?, name = ?", sql)
+	assert.Equal(t, []any{1, "moe", 2, "larry"}, args)
+}
+
+func TestInsertOnConflictSelect(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("dst").Columns("id", "name").
+		Select(Select("id", "name").From("src").Where("id > ?", 10)).
+		OnConflict("id").DoUpdate().SetExcluded("name").End().
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO dst (id,name) SELECT id, name FROM src WHERE id > $1 "+
+		"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name", sql)
+	assert.Equal(t, []any{10}, args)
+}
+
+func TestInsertOnConflictErr(t *testing.T) {
+	t.Parallel()
+	b := Insert("users").Columns("id", "name").Values(1, "moe")
+
+	_, _, err := InsertBuilder(b.OnConflict("id")).ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.OnConflict().DoUpdate().SetExcluded("name").ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.OnConflict("id").DoUpdate().ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.OnConflict().Where("deleted_at IS NULL").DoNothing().ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.OnConstraint("users_pkey").Where("deleted_at IS NULL").DoNothing().ToSql()
+	require.Error(t, err)
+}
+
+func TestInsertOnDuplicateKeyUpdate(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("users").Columns("id", "name", "visits").Values(1, "moe", 1).
+		OnDuplicateKeyUpdate().
+		SetExcluded("name").
+		Set("visits", Expr("visits + ?", 1)).
+		End().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,visits) VALUES (?,?,?) "+
+		"ON DUPLICATE KEY UPDATE name = VALUES(name), visits = visits + ?", sql)
+	assert.Equal(t, []any{1, "moe", 1, 1}, args)
+
+	sql, _, err = Insert("users").Columns("id", "name").Values(1, "moe").
+		RowAlias("new").
+		OnDuplicateKeyUpdate().SetExcluded("name").End().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) AS new ON DUPLICATE KEY UPDATE name = new.name", sql)
+
+	sql, _, err = Insert("users").Columns("id", "name").Values(1, "moe").Ignore().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO users (id,name) VALUES (?,?)", sql)
+}
+
+func TestInsertOnConflictMySQLDialect(t *testing.T) {
+	t.Parallel()
+	b := Insert("users").Columns("id", "name").Values(1, "moe").Dialect(DialectMySQL)
+
+	sql, _, err := b.OnConflict("id").DoUpdate().SetExcluded("name").End().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) ON DUPLICATE KEY UPDATE name = VALUES(name)", sql)
+
+	sql, _, err = b.OnConflict().DoNothing().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO users (id,name) VALUES (?,?)", sql)
+
+	sql, _, err = StatementBuilder.Dialect(DialectMySQL).
+		Insert("users").Columns("id", "name").Values(1, "moe").
+		OnConflict("id").DoUpdate().SetExcluded("name").End().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?) ON DUPLICATE KEY UPDATE name = VALUES(name)", sql)
+}
+
+func TestInsertOnDuplicateKeyUpdateErr(t *testing.T) {
+	t.Parallel()
+	b := Insert("users").Columns("id", "name").Values(1, "moe")
+
+	_, _, err := b.OnDuplicateKeyUpdate().SetExcluded("name").End().PlaceholderFormat(Dollar).ToSql()
+	require.EqualError(t, err, "ON DUPLICATE KEY UPDATE is not supported by the postgres dialect")
+
+	_, _, err = b.Ignore().Dialect(DialectSQLServer).ToSql()
+	require.EqualError(t, err, "INSERT IGNORE is not supported by the sqlserver dialect")
+
+	_, _, err = b.Ignore().Dialect(DialectMySQL).PlaceholderFormat(Dollar).ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.OnDuplicateKeyUpdate().End().ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.Dialect(DialectMySQL).OnConstraint("users_pkey").DoNothing().ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.Dialect(DialectMySQL).OnConflict("id").DoUpdate().SetExcluded("name").Where("id > 0").End().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Replace("users").Columns("id", "name").Values(1, "moe").Ignore().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("users").Columns("id").Select(Select("id").From("src")).RowAlias("new").ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.OnConflict("id").DoNothing().Dialect(DialectOracle).ToSql()
+	require.EqualError(t, err, "ON CONFLICT is not supported by the oracle dialect")
+}
@@ -0,0 +1,105 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// copyTarget returns the table and columns of the insert query for bulk copy,
+// or an error if the query uses anything COPY cannot express.
+func (d *insertData) copyTarget() (table, columns []string, err error) {
+	switch {
+	case d.Err != nil:
+		return nil, nil, d.Err
+	case d.Into == "":
+		return nil, nil, errors.New("copy from must specify a table")
+	case len(d.Columns) == 0:
+		return nil, nil, errors.New("copy from must specify columns")
+	case d.Select != nil:
+		return nil, nil, errors.New("copy from cannot be used with insert select")
+	case len(d.Prefixes) > 0 || len(d.Suffixes) > 0:
+		return nil, nil, errors.New("copy from cannot be used with prefixes or suffixes")
+	case len(d.Options) > 0 || d.StatementKeyword != "":
+		return nil, nil, errors.New("copy from cannot be used with statement options")
+	case d.hasConflict() || d.DuplicateKey || d.RowAlias != "":
+		return nil, nil, errors.New("copy from cannot be used with conflict clause")
+	case len(d.Returning) > 0:
+		return nil, nil, errors.New("copy from cannot be used with returning clause")
+	}
+
+	return strings.Split(d.Into, "."), d.Columns, nil
+}
+
+// checkCopyRow returns an error if the row cannot be copied into the columns.
+func checkCopyRow(i int, row []any, columns int) error {
+	if len(row) != columns {
+		return fmt.Errorf("copy from row %d has %d values for %d columns", i, len(row), columns)
+	}
+	for _, val := range row {
+		if _, ok := val.(Sqlizer); ok {
+			return errors.New("copy from does not support Sqlizer values")
+		}
+	}
+	return nil
+}
+
+// copyRows returns the table, columns and rows of the insert query for bulk copy.
+func (d *insertData) copyRows() (table, columns []string, rows [][]any, err error) {
+	if table, columns, err = d.copyTarget(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	for i, row := range d.Values {
+		if err = checkCopyRow(i, row, len(columns)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return table, columns, d.Values, nil
+}
+
+// CopyRows returns the table ("schema.table" is split into parts), columns and rows of the insert
+// query for bulk copy APIs, e.g. PostgreSQL COPY protocol (see pgxcopy package).
+// The rows are taken from Values (or SetMap, Struct, Structs), so they are kept in memory,
+// use CopyRowsSeq to stream huge imports.
+//
+// It returns an error if the query uses anything COPY cannot express:
+// Select, Sqlizer values, prefixes, suffixes, conflict or returning clauses.
+func (b InsertBuilder) CopyRows() (table, columns []string, rows [][]any, err error) {
+	data := builder.GetStruct(b).(insertData)
+	return data.copyRows()
+}
+
+// CopyRowsSeq works like CopyRows, but takes the rows from the sequence, so that
+// huge imports can be streamed without keeping all rows in memory.
+// The query is used as a template, its own Values are ignored.
+//
+// The rows are checked while the returned sequence is iterated: an invalid row
+// is yielded with the error and ends the sequence.
+func (b InsertBuilder) CopyRowsSeq(
+	rows iter.Seq[[]any],
+) (table, columns []string, seq iter.Seq2[[]any, error], err error) {
+	data := builder.GetStruct(b).(insertData)
+	if table, columns, err = data.copyTarget(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	seq = func(yield func([]any, error) bool) {
+		i := 0
+		for row := range rows {
+			if err := checkCopyRow(i, row, len(columns)); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+			i++
+		}
+	}
+	return table, columns, seq, nil
+}
@@ -0,0 +1,62 @@
+package squirrel
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertCopyRows(t *testing.T) {
+	t.Parallel()
+	table, columns, rows, err := Insert("public.users").Columns("id", "name").Values(1, "moe").Values(2, "larry").
+		CopyRows()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"public", "users"}, table)
+	assert.Equal(t, []string{"id", "name"}, columns)
+	assert.Equal(t, [][]any{{1, "moe"}, {2, "larry"}}, rows)
+}
+
+func TestInsertCopyRowsErr(t *testing.T) {
+	t.Parallel()
+	b := Insert("users").Columns("id", "name").Values(1, "moe")
+
+	for _, q := range []InsertBuilder{
+		Insert("users").Values(1, "moe"),
+		b.Values(2),
+		b.Values(2, Expr("now()")),
+		Insert("users").Columns("id").Select(Select("id").From("src")),
+		b.Suffix("RETURNING id"),
+		b.Prefix("WITH x AS (SELECT 1)"),
+		b.Options("IGNORE"),
+		b.OnConflict().DoNothing(),
+		b.Returning("id"),
+		Insert("users").Struct(nil),
+	} {
+		_, _, _, err := q.CopyRows()
+		require.Error(t, err)
+	}
+}
+
+func TestInsertCopyRowsSeq(t *testing.T) {
+	t.Parallel()
+	table, columns, seq, err := Insert("users").Columns("id", "name").Values(0, "ignored").
+		CopyRowsSeq(slices.Values([][]any{{1, "moe"}, {2, Expr("now()")}, {3, "curly"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users"}, table)
+	assert.Equal(t, []string{"id", "name"}, columns)
+
+	var rows [][]any
+	for row, err := range seq {
+		if err != nil {
+			require.EqualError(t, err, "copy from does not support Sqlizer values")
+			break
+		}
+		rows = append(rows, row)
+	}
+	assert.Equal(t, [][]any{{1, "moe"}}, rows)
+
+	_, _, _, err = Insert("users").CopyRowsSeq(slices.Values([][]any{{1}}))
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package squirrel
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/lann/builder"
 )
@@ -18,8 +19,11 @@ import (
 
 type commonTableExpressionsData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	Recursive         bool
 	CurrentCteName    string
+	CurrentColumns    []string           // column list of the current cte
+	CurrentHint       cteMaterialization // MATERIALIZED hint of the current cte
 	Ctes              []Sqlizer
 	Statement         Sqlizer
 }
@@ -33,19 +37,21 @@ func (d *commonTableExpressionsData) toSql() (sqlStr string, args []any, err err
 	if d.Statement == nil {
 		err = errors.New(
 			"common table expressions must one of the following final statement: " +
-				"(select, insert, replace, update, delete)",
+				"(select, insert, replace, update, delete, merge)",
 		)
 		return "", nil, err
 	}
 
 	sql := &bytes.Buffer{}
 
+	dialect := resolveDialect(d.Dialect, d.PlaceholderFormat)
+
 	_, _ = sql.WriteString("WITH ")
 	if d.Recursive {
-		_, _ = sql.WriteString("RECURSIVE ")
+		_, _ = sql.WriteString(recursiveKeyword(dialect))
 	}
 
-	args, err = appendToSql(d.Ctes, sql, ", ", args)
+	args, err = writeCtes(sql, d.Ctes, dialect, args)
 	if err != nil {
 		return "", nil, err
 	}
@@ -64,6 +70,32 @@ func (d *commonTableExpressionsData) ToSql() (sql string, args []any, err error)
 	return d.toSql()
 }
 
+// checkCteBody checks that the statement can be used as CTE body: data-modifying statements
+// must return rows and VALUES list must have no alias.
+func checkCteBody(cte string, body Sqlizer) error {
+	var returning []any
+	switch b := body.(type) {
+	case InsertBuilder:
+		returning = builder.GetStruct(b).(insertData).Returning
+	case UpdateBuilder:
+		returning = builder.GetStruct(b).(updateData).Returning
+	case DeleteBuilder:
+		returning = builder.GetStruct(b).(deleteData).Returning
+	case ValuesBuilder:
+		if builder.GetStruct(b).(valuesData).Alias != "" {
+			return fmt.Errorf("values list of cte %s must not have an alias", cte)
+		}
+		return nil
+	default:
+		return nil
+	}
+
+	if len(returning) == 0 {
+		return fmt.Errorf("data-modifying statement of cte %s must have a Returning clause", cte)
+	}
+	return nil
+}
+
 // Builder
 
 // CommonTableExpressionsBuilder builds CTE (Common Table Expressions) SQL statements.
@@ -82,6 +114,12 @@ func (b CommonTableExpressionsBuilder) PlaceholderFormat(f PlaceholderFormat) Co
 	return builder.Set(b, "PlaceholderFormat", f).(CommonTableExpressionsBuilder)
 }
 
+// Dialect sets the SQL dialect of the query. If not set, the dialect is
+// detected by the PlaceholderFormat.
+func (b CommonTableExpressionsBuilder) Dialect(d Dialect) CommonTableExpressionsBuilder {
+	return builder.Set(b, "Dialect", d).(CommonTableExpressionsBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -104,15 +142,39 @@ func (b CommonTableExpressionsBuilder) Recursive(recursive bool) CommonTableExpr
 	return builder.Set(b, "Recursive", recursive).(CommonTableExpressionsBuilder)
 }
 
-// Cte starts a new cte.
-func (b CommonTableExpressionsBuilder) Cte(cte string) CommonTableExpressionsBuilder {
+// Cte starts a new cte. Optional columns are rendered as the column list: "cte(a, b) AS (...)".
+func (b CommonTableExpressionsBuilder) Cte(cte string, columns ...string) CommonTableExpressionsBuilder {
+	b = builder.Set(b, "CurrentColumns", columns).(CommonTableExpressionsBuilder)
+	b = builder.Set(b, "CurrentHint", cteMaterializedDefault).(CommonTableExpressionsBuilder)
 	return builder.Set(b, "CurrentCteName", cte).(CommonTableExpressionsBuilder)
 }
 
-// As sets the expression for the Cte.
-func (b CommonTableExpressionsBuilder) As(as SelectBuilder) CommonTableExpressionsBuilder {
+// Materialized adds MATERIALIZED hint to the current cte: "cte AS MATERIALIZED (...)".
+// Supported by PostgreSQL and SQLite.
+func (b CommonTableExpressionsBuilder) Materialized() CommonTableExpressionsBuilder {
+	return builder.Set(b, "CurrentHint", cteMaterialized).(CommonTableExpressionsBuilder)
+}
+
+// NotMaterialized adds NOT MATERIALIZED hint to the current cte: "cte AS NOT MATERIALIZED (...)".
+// Supported by PostgreSQL and SQLite.
+func (b CommonTableExpressionsBuilder) NotMaterialized() CommonTableExpressionsBuilder {
+	return builder.Set(b, "CurrentHint", cteNotMaterialized).(CommonTableExpressionsBuilder)
+}
+
+// As sets the expression for the Cte: SelectBuilder, ValuesBuilder (without alias) or
+// data-modifying InsertBuilder, UpdateBuilder and DeleteBuilder with Returning clause (PostgreSQL), e.g.
+// WITH moved AS (DELETE FROM a WHERE ... RETURNING *) INSERT INTO b SELECT * FROM moved.
+// ToSql returns an error for other forms of these builders.
+// Placeholders are numbered across all CTEs and the final statement.
+func (b CommonTableExpressionsBuilder) As(as Sqlizer) CommonTableExpressionsBuilder {
 	data := builder.GetStruct(b).(commonTableExpressionsData)
-	return builder.Append(b, "Ctes", cteExpr{as, data.CurrentCteName}).(CommonTableExpressionsBuilder)
+	cte := cteExpr{expr: as, cte: data.CurrentCteName, columns: data.CurrentColumns, materialized: data.CurrentHint}
+	return builder.Append(b, "Ctes", cte).(CommonTableExpressionsBuilder)
+}
+
+// RecursiveCte adds the recursive CTE to the query and sets the RECURSIVE option.
+func (b CommonTableExpressionsBuilder) RecursiveCte(cte RecursiveCteBuilder) CommonTableExpressionsBuilder {
+	return builder.Append(b.Recursive(true), "Ctes", cte).(CommonTableExpressionsBuilder)
 }
 
 // Select finalizes the CommonTableExpressionsBuilder with a SELECT.
@@ -139,3 +201,8 @@ func (b CommonTableExpressionsBuilder) Update(statement UpdateBuilder) CommonTab
 func (b CommonTableExpressionsBuilder) Delete(statement DeleteBuilder) CommonTableExpressionsBuilder {
 	return builder.Set(b, "Statement", statement).(CommonTableExpressionsBuilder)
 }
+
+// Merge finalizes the CommonTableExpressionsBuilder with a MERGE.
+func (b CommonTableExpressionsBuilder) Merge(statement MergeBuilder) CommonTableExpressionsBuilder {
+	return builder.Set(b, "Statement", statement).(CommonTableExpressionsBuilder)
+}
@@ -140,7 +140,8 @@ func TestCTEPlaceholderFormat(t *testing.T) {
 			Where(Eq{"col1": 1})).
 		Update(
 			Update("table2").
-				Set("col3", 2))
+				Set("col3", 2).
+				AllowFullTable())
 
 	sql, _, err := q.PlaceholderFormat(Question).ToSql()
 	require.NoError(t, err)
@@ -251,3 +252,95 @@ func TestWithAsQuery_Delete(t *testing.T) {
 
 	assert.Equal(t, expectedSql, q)
 }
+
+func TestWithAsQuery_ColumnsAndMaterialized(t *testing.T) {
+	t.Parallel()
+	q := With("lab_1", "a", "b").Materialized().As(
+		Select("col1", "col2").From("tab1").Where("col1 = ?", 1),
+	).Cte("lab_2").NotMaterialized().As(
+		Select("col3").From("tab2"),
+	).Cte("lab_3", "c").As(
+		Select("col4").From("tab3"),
+	).Select(
+		Select("*").From("lab_1").Join("lab_2 ON true").Join("lab_3 ON true"),
+	).PlaceholderFormat(Dollar)
+
+	sql, args, err := q.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH lab_1(a, b) AS MATERIALIZED (SELECT col1, col2 FROM tab1 WHERE col1 = $1), " +
+		"lab_2 AS NOT MATERIALIZED (SELECT col3 FROM tab2), " +
+		"lab_3(c) AS (SELECT col4 FROM tab3) " +
+		"SELECT * FROM lab_1 JOIN lab_2 ON true JOIN lab_3 ON true"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1}, args)
+
+	_, _, err = q.Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "CTE MATERIALIZED hint is not supported by the mysql dialect")
+
+	sql, _, err = Cte(Select("x").From("t"), "c", "y").Materialized().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "c(y) AS MATERIALIZED (SELECT x FROM t)", sql)
+}
+
+func TestWithAsQuery_DataModifying(t *testing.T) {
+	t.Parallel()
+	b := StatementBuilder.PlaceholderFormat(Dollar)
+
+	q := b.With("moved").As(
+		b.Delete("a").Where("created < ?", "2024-01-01").Returning("*"),
+	).Cte("updated").As(
+		b.Update("c").Set("moved", true).Where("id = ?", 2).Returning("id"),
+	).Cte("added").As(
+		b.Insert("log").Columns("msg").Values("moved").Returning("id"),
+	).Insert(
+		b.Insert("b").Select(b.Select("*").From("moved").Where("kind = ?", "x")),
+	)
+
+	sql, args, err := q.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH moved AS (DELETE FROM a WHERE created < $1 RETURNING *), " +
+		"updated AS (UPDATE c SET moved = $2 WHERE id = $3 RETURNING id), " +
+		"added AS (INSERT INTO log (msg) VALUES ($4) RETURNING id) " +
+		"INSERT INTO b SELECT * FROM moved WHERE kind = $5"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{"2024-01-01", true, 2, "moved", "x"}, args)
+}
+
+func TestWithAsQuery_Values(t *testing.T) {
+	t.Parallel()
+	q := With("v", "id", "qty").As(
+		Values([][]any{{1, 10}, {2, 20}}),
+	).Update(
+		Update("items").Set("qty", Expr("v.qty")).From("v").Where("items.id = v.id").Where("items.qty < ?", 5),
+	).PlaceholderFormat(Dollar)
+
+	sql, args, err := q.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH v(id, qty) AS (VALUES ($1, $2), ($3, $4)) " +
+		"UPDATE items SET qty = v.qty FROM v WHERE items.id = v.id AND items.qty < $5"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, 10, 2, 20, 5}, args)
+}
+
+func TestWithAsQuery_BodyErr(t *testing.T) {
+	t.Parallel()
+	final := Select("*").From("x")
+
+	_, _, err := With("x").As(Values([][]any{{1, 2}}).As("v", "a", "b")).Select(final).ToSql()
+	require.EqualError(t, err, "values list of cte x must not have an alias")
+
+	_, _, err = With("x").As(Delete("a").Where("id = ?", 1)).Select(final).ToSql()
+	require.EqualError(t, err, "data-modifying statement of cte x must have a Returning clause")
+
+	_, _, err = With("x").As(Update("a").Set("b", 1).Where("id = ?", 1)).Select(final).ToSql()
+	require.EqualError(t, err, "data-modifying statement of cte x must have a Returning clause")
+
+	_, _, err = With("x").As(Insert("a").Values(1)).Select(final).ToSql()
+	require.EqualError(t, err, "data-modifying statement of cte x must have a Returning clause")
+
+	_, _, err = final.WithCte(Cte(Insert("a").Values(1), "x")).ToSql()
+	require.EqualError(t, err, "data-modifying statement of cte x must have a Returning clause")
+}
@@ -3,7 +3,6 @@ package squirrel
 import (
 	"bytes"
 	"errors"
-	"strconv"
 	"strings"
 
 	"github.com/lann/builder"
@@ -11,13 +10,79 @@ import (
 
 type deleteData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	Prefixes          []Sqlizer
 	From              string
+	Using             []Sqlizer
+	Joins             []Sqlizer
 	WhereParts        []Sqlizer
 	OrderBys          []string
-	Limit             string
-	Offset            string
+	Limit             *uint64
+	Offset            *uint64
+	BindLimitOffset   bool     // LIMIT and OFFSET values are passed as args.
+	LimitKey          []string // row key columns of the postgres LIMIT emulation, see LimitKey
+	Returning         []any
 	Suffixes          []Sqlizer
+	VersionColumn     string // optimistic locking column, see WithVersion
+	Version           any
+	SoftDeletes       []softDeleteTable
+	HardDelete        bool // soft delete is disabled, see HardDelete
+	AllowFullTable    bool // statement without WHERE conditions is allowed, see ErrFullTable
+}
+
+// writeDeleteClause writes DELETE clause with the source tables and SQL Server OUTPUT clause.
+func (d *deleteData) writeDeleteClause(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	var err error
+	multiTable := len(d.Using) > 0 || len(d.Joins) > 0
+
+	switch {
+	case !multiTable:
+		_, _ = sql.WriteString("DELETE FROM ")
+		_, _ = sql.WriteString(d.From)
+		if dialect == DialectSQLServer {
+			return writeReturning(sql, d.Returning, dialect, outputDeleted, args)
+		}
+		return args, nil
+
+	case dialect == DialectMySQL || dialect == DialectSQLServer:
+		// DELETE t FROM t, u JOIN j ON ...
+		_, _ = sql.WriteString("DELETE ")
+		_, _ = sql.WriteString(tableRef(d.From))
+		if dialect == DialectSQLServer {
+			if args, err = writeReturning(sql, d.Returning, dialect, outputDeleted, args); err != nil {
+				return nil, err
+			}
+		}
+		_, _ = sql.WriteString(" FROM ")
+		_, _ = sql.WriteString(d.From)
+		if len(d.Using) > 0 {
+			_, _ = sql.WriteString(", ")
+			if args, err = appendToSql(d.Using, sql, ", ", args); err != nil {
+				return nil, err
+			}
+		}
+
+	case dialect == DialectPostgres || dialect == DialectUndefined:
+		// DELETE FROM t USING u JOIN j ON ...
+		if len(d.Using) == 0 {
+			return nil, errors.New("delete joins require Using table for the postgres dialect")
+		}
+		_, _ = sql.WriteString("DELETE FROM ")
+		_, _ = sql.WriteString(d.From)
+		_, _ = sql.WriteString(" USING ")
+		if args, err = appendToSql(d.Using, sql, ", ", args); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, errUnsupported("DELETE with USING or joins", dialect)
+	}
+
+	if len(d.Joins) == 0 {
+		return args, nil
+	}
+	_, _ = sql.WriteString(" ")
+	return appendToSql(d.Joins, sql, " ", args)
 }
 
 func (d *deleteData) toSqlRaw() (sqlStr string, args []any, err error) {
@@ -26,6 +91,31 @@ func (d *deleteData) toSqlRaw() (sqlStr string, args []any, err error) {
 		return "", nil, err
 	}
 
+	dialect := resolveDialect(d.Dialect, d.PlaceholderFormat)
+	if err = checkReturning(dialect, d.Returning); err != nil {
+		return "", nil, err
+	}
+
+	if !d.AllowFullTable {
+		if err = checkFullTable(dialect, d.WhereParts, d.Joins); err != nil {
+			return "", nil, err
+		}
+	}
+
+	soft, ok, err := d.softDeleteUpdate()
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		return soft.toSqlRaw()
+	}
+
+	if needsLimitEmulation(dialect, d.OrderBys, d.Limit, d.Offset) {
+		if d, err = d.emulateLimit(); err != nil {
+			return "", nil, err
+		}
+	}
+
 	sql := &bytes.Buffer{}
 
 	if len(d.Prefixes) > 0 {
@@ -37,8 +127,9 @@ func (d *deleteData) toSqlRaw() (sqlStr string, args []any, err error) {
 		_, _ = sql.WriteString(" ")
 	}
 
-	_, _ = sql.WriteString("DELETE FROM ")
-	_, _ = sql.WriteString(d.From)
+	if args, err = d.writeDeleteClause(sql, dialect, args); err != nil {
+		return "", nil, err
+	}
 
 	if len(d.WhereParts) > 0 {
 		_, _ = sql.WriteString(" WHERE ")
@@ -48,19 +139,23 @@ func (d *deleteData) toSqlRaw() (sqlStr string, args []any, err error) {
 		}
 	}
 
+	if dialect != DialectSQLServer {
+		if args, err = writeReturning(sql, d.Returning, dialect, outputDeleted, args); err != nil {
+			return "", nil, err
+		}
+	}
+
 	if len(d.OrderBys) > 0 {
 		_, _ = sql.WriteString(" ORDER BY ")
 		_, _ = sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
-	if d.Limit != "" {
-		_, _ = sql.WriteString(" LIMIT ")
-		_, _ = sql.WriteString(d.Limit)
+	if d.Limit != nil {
+		args = writeLimitValue(sql, "LIMIT", *d.Limit, d.BindLimitOffset, args)
 	}
 
-	if d.Offset != "" {
-		_, _ = sql.WriteString(" OFFSET ")
-		_, _ = sql.WriteString(d.Offset)
+	if d.Offset != nil {
+		args = writeLimitValue(sql, "OFFSET", *d.Offset, d.BindLimitOffset, args)
 	}
 
 	if len(d.Suffixes) > 0 {
@@ -100,6 +195,12 @@ func (b DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) DeleteBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(DeleteBuilder)
 }
 
+// Dialect sets the SQL dialect of the query. If not set, the dialect is
+// detected by the PlaceholderFormat.
+func (b DeleteBuilder) Dialect(d Dialect) DeleteBuilder {
+	return builder.Set(b, "Dialect", d).(DeleteBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -133,6 +234,48 @@ func (b DeleteBuilder) From(from string) DeleteBuilder {
 	return builder.Set(b, "From", from).(DeleteBuilder)
 }
 
+// Using adds tables to USING clause of the query, which can be referenced in WHERE clause.
+//
+// For PostgreSQL (and undefined dialect) it renders "DELETE FROM t USING u WHERE ...",
+// for MySQL and SQL Server "DELETE t FROM t, u WHERE ...". Other dialects return an error.
+func (b DeleteBuilder) Using(tables ...string) DeleteBuilder {
+	for _, table := range tables {
+		b = builder.Append(b, "Using", newPart(table)).(DeleteBuilder)
+	}
+	return b
+}
+
+// UsingSelect adds a subquery to USING clause of the query.
+//
+// See Using for more information.
+func (b DeleteBuilder) UsingSelect(from SelectBuilder, alias string) DeleteBuilder {
+	return builder.Append(b, "Using", Alias(from, alias)).(DeleteBuilder)
+}
+
+// JoinClause adds a join clause to the query.
+//
+// For MySQL and SQL Server it renders "DELETE t FROM t JOIN j ON ... WHERE ...".
+// PostgreSQL does not support joins to the deleted table, so the joins are added
+// after the Using tables: "DELETE FROM t USING u JOIN j ON ... WHERE ...".
+func (b DeleteBuilder) JoinClause(pred any, args ...any) DeleteBuilder {
+	return builder.Append(b, "Joins", newJoinPart(pred, args...)).(DeleteBuilder)
+}
+
+// Join adds a JOIN clause to the query.
+func (b DeleteBuilder) Join(join string, rest ...any) DeleteBuilder {
+	return b.JoinClause("JOIN "+join, rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b DeleteBuilder) LeftJoin(join string, rest ...any) DeleteBuilder {
+	return b.JoinClause("LEFT JOIN "+join, rest...)
+}
+
+// InnerJoin adds a INNER JOIN clause to the query.
+func (b DeleteBuilder) InnerJoin(join string, rest ...any) DeleteBuilder {
+	return b.JoinClause("INNER JOIN "+join, rest...)
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -147,12 +290,29 @@ func (b DeleteBuilder) OrderBy(orderBys ...string) DeleteBuilder {
 
 // Limit sets a LIMIT clause on the query.
 func (b DeleteBuilder) Limit(limit uint64) DeleteBuilder {
-	return builder.Set(b, "Limit", strconv.FormatUint(limit, 10)).(DeleteBuilder)
+	return builder.Set(b, "Limit", &limit).(DeleteBuilder)
 }
 
 // Offset sets a OFFSET clause on the query.
 func (b DeleteBuilder) Offset(offset uint64) DeleteBuilder {
-	return builder.Set(b, "Offset", strconv.FormatUint(offset, 10)).(DeleteBuilder)
+	return builder.Set(b, "Offset", &offset).(DeleteBuilder)
+}
+
+// BindLimitOffset makes LIMIT and OFFSET values to be passed as args.
+//
+// See SelectBuilder.BindLimitOffset for more information.
+func (b DeleteBuilder) BindLimitOffset(bind bool) DeleteBuilder {
+	return builder.Set(b, "BindLimitOffset", bind).(DeleteBuilder)
+}
+
+// Returning adds RETURNING clause columns to the query. Columns can be strings
+// or Sqlizers, e.g. "id" or Old("name").
+//
+// For SQL Server OUTPUT clause is rendered before WHERE clause, and plain column
+// names are qualified with "deleted". RETURNING is not supported by MySQL and
+// Oracle dialects.
+func (b DeleteBuilder) Returning(columns ...any) DeleteBuilder {
+	return builder.Extend(b, "Returning", columns).(DeleteBuilder)
 }
 
 // toSqlRaw builds SQL with raw placeholders ("?") without applying PlaceholderFormat.
@@ -56,3 +56,61 @@ func TestDeleteBuilderPlaceholders(t *testing.T) {
 	sql, _, _ = b.PlaceholderFormat(Dollar).ToSql()
 	assert.Equal(t, "DELETE FROM test WHERE x = $1 AND y = $2", sql)
 }
+
+func TestDeleteBuilderUsing(t *testing.T) {
+	t.Parallel()
+	b := Delete("orders o").
+		Using("customers c").
+		Where("o.customer_id = c.id AND c.blocked = ?", true)
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM orders o USING customers c WHERE o.customer_id = c.id AND c.blocked = $1", sql)
+	assert.Equal(t, []any{true}, args)
+
+	sql, _, err = b.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE o FROM orders o, customers c WHERE o.customer_id = c.id AND c.blocked = ?", sql)
+
+	sql, args, err = Delete("orders").
+		UsingSelect(Select("id").From("customers").Where("blocked = ?", true), "c").
+		Where("orders.customer_id = c.id").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM orders USING (SELECT id FROM customers WHERE blocked = $1) AS c "+
+		"WHERE orders.customer_id = c.id", sql)
+	assert.Equal(t, []any{true}, args)
+}
+
+func TestDeleteBuilderJoin(t *testing.T) {
+	t.Parallel()
+	b := Delete("orders o").
+		Join("customers c ON c.id = o.customer_id").
+		Where("c.blocked = ?", true)
+
+	sql, _, err := b.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE o FROM orders o JOIN customers c ON c.id = o.customer_id WHERE c.blocked = ?", sql)
+
+	sql, _, err = b.Returning("id").PlaceholderFormat(AtP).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE o OUTPUT deleted.id FROM orders o JOIN customers c ON c.id = o.customer_id "+
+		"WHERE c.blocked = @p1", sql)
+
+	sql, _, err = Delete("orders o").
+		Using("customers c").
+		LeftJoin("regions r ON r.id = c.region_id").
+		Where("o.customer_id = c.id AND r.id IS NULL").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM orders o USING customers c LEFT JOIN regions r ON r.id = c.region_id "+
+		"WHERE o.customer_id = c.id AND r.id IS NULL", sql)
+
+	_, _, err = b.PlaceholderFormat(Dollar).ToSql()
+	require.Error(t, err)
+
+	_, _, err = b.Dialect(DialectSQLite).ToSql()
+	require.EqualError(t, err, "DELETE with USING or joins is not supported by the sqlite dialect")
+}
@@ -0,0 +1,61 @@
+package squirrel
+
+import "fmt"
+
+// Dialect is the SQL dialect of the generated statements.
+// It selects the syntax of the clauses that differ between databases.
+type Dialect int
+
+const (
+	// DialectUndefined means that the dialect is detected by the PlaceholderFormat:
+	// Dollar is PostgreSQL, AtP is SQL Server, Colon is Oracle.
+	// Question leaves the dialect undefined, and the generic (mostly PostgreSQL-like) syntax is used.
+	DialectUndefined Dialect = iota
+	DialectPostgres
+	DialectMySQL
+	DialectSQLite
+	DialectSQLServer
+	DialectOracle
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectPostgres:
+		return "postgres"
+	case DialectMySQL:
+		return "mysql"
+	case DialectSQLite:
+		return "sqlite"
+	case DialectSQLServer:
+		return "sqlserver"
+	case DialectOracle:
+		return "oracle"
+	case DialectUndefined:
+	}
+	return "undefined"
+}
+
+// resolveDialect returns the dialect if it is set explicitly, otherwise
+// detects it by the placeholder format.
+func resolveDialect(d Dialect, f PlaceholderFormat) Dialect {
+	if d != DialectUndefined {
+		return d
+	}
+
+	switch f.(type) {
+	case dollarFormat:
+		return DialectPostgres
+	case atpFormat:
+		return DialectSQLServer
+	case colonFormat:
+		return DialectOracle
+	}
+
+	return DialectUndefined
+}
+
+// errUnsupported returns an error for a feature not supported by the dialect.
+func errUnsupported(feature string, d Dialect) error {
+	return fmt.Errorf("%s is not supported by the %s dialect", feature, d)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"strings"
@@ -140,6 +141,20 @@ func (e aliasExpr) ToSql() (sql string, args []any, err error) {
 	return
 }
 
+// keywordExpr is an SQL keyword used in place of a value.
+type keywordExpr string
+
+func (k keywordExpr) ToSql() (sql string, args []any, err error) {
+	return string(k), nil, nil
+}
+
+// Default renders as DEFAULT keyword, so the column gets its default value.
+// Can be used in InsertBuilder.Values, InsertBuilder.SetMap and UpdateBuilder.Set
+// Ex:
+//
+//	Insert("t").Columns("id", "created_at").Values(1, Default).Values(2, time.Now())
+const Default keywordExpr = "DEFAULT"
+
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 type Eq map[string]any
 
@@ -865,23 +880,99 @@ func clearEmptyValue(v any) any {
 	return nil
 }
 
+// cteMaterialization is MATERIALIZED hint of CTE.
+type cteMaterialization int
+
+const (
+	cteMaterializedDefault cteMaterialization = iota
+	cteMaterialized
+	cteNotMaterialized
+)
+
 type cteExpr struct {
-	expr Sqlizer
-	cte  string
+	expr         Sqlizer
+	cte          string
+	columns      []string
+	materialized cteMaterialization
 }
 
 // Cte allows to define CTE (Common Table Expressions) in SQL query.
-func Cte(e Sqlizer, cte string) cteExpr {
-	return cteExpr{e, cte}
+// Optional columns are rendered as the column list: "cte(a, b) AS (...)".
+func Cte(e Sqlizer, cte string, columns ...string) cteExpr {
+	return cteExpr{expr: e, cte: cte, columns: columns, materialized: cteMaterializedDefault}
+}
+
+// Materialized adds MATERIALIZED hint to the CTE: "cte AS MATERIALIZED (...)".
+// Supported by PostgreSQL and SQLite.
+func (e cteExpr) Materialized() cteExpr {
+	e.materialized = cteMaterialized
+	return e
+}
+
+// NotMaterialized adds NOT MATERIALIZED hint to the CTE: "cte AS NOT MATERIALIZED (...)".
+// Supported by PostgreSQL and SQLite.
+func (e cteExpr) NotMaterialized() cteExpr {
+	e.materialized = cteNotMaterialized
+	return e
 }
 
 // ToSql builds the query into a SQL string and bound args.
 func (e cteExpr) ToSql() (sql string, args []any, err error) {
+	return e.toSqlDialect(DialectUndefined)
+}
+
+func (e cteExpr) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	if err = checkCteBody(e.cte, e.expr); err != nil {
+		return "", nil, err
+	}
+
+	var hint string
+	switch e.materialized {
+	case cteMaterializedDefault:
+	case cteMaterialized:
+		hint = "MATERIALIZED "
+	case cteNotMaterialized:
+		hint = "NOT MATERIALIZED "
+	}
+
+	if hint != "" && dialect != DialectPostgres && dialect != DialectSQLite && dialect != DialectUndefined {
+		return "", nil, errUnsupported("CTE MATERIALIZED hint", dialect)
+	}
+
+	name := e.cte
+	if len(e.columns) > 0 {
+		name = fmt.Sprintf("%s(%s)", name, strings.Join(e.columns, ", "))
+	}
+
 	sql, args, err = nestedToSql(e.expr)
-	if err == nil {
-		sql = fmt.Sprintf("%s AS (%s)", e.cte, sql)
+	if err != nil {
+		return "", nil, err
 	}
-	return
+	return fmt.Sprintf("%s AS %s(%s)", name, hint, sql), args, nil
+}
+
+// writeCtes writes CTE list "a AS (...), b AS (...)" applying the dialect to CTE expressions.
+func writeCtes(sql io.Writer, ctes []Sqlizer, dialect Dialect, args []any) ([]any, error) {
+	for i, cte := range ctes {
+		var cteSql string
+		var cteArgs []any
+		var err error
+		if ds, ok := cte.(dialectSqlizer); ok {
+			cteSql, cteArgs, err = ds.toSqlDialect(dialect)
+		} else {
+			cteSql, cteArgs, err = nestedToSql(cte)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			_, _ = io.WriteString(sql, ", ")
+		}
+		_, _ = io.WriteString(sql, cteSql)
+		args = append(args, cteArgs...)
+	}
+	return args, nil
 }
 
 type notExpr struct {
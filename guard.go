@@ -0,0 +1,167 @@
+package squirrel
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// ErrFullTable is returned by UPDATE and DELETE builders if the statement has no WHERE
+// conditions or all of them are always true, e.g. empty In or EqNotEmpty with empty values.
+// Inner joins with ON or USING clause count as a filter for MySQL and SQL Server dialects,
+// where the joins of multi-table statements restrict the affected rows.
+// Use AllowFullTable to affect all rows of the table intentionally.
+var ErrFullTable = errors.New("statement without WHERE conditions affects all rows of the table")
+
+// alwaysTrueConditions are the conditions generated for empty expressions.
+//
+//nolint:gochecknoglobals // read-only list of conditions
+var alwaysTrueConditions = []string{"", "1=1", "TRUE"}
+
+// trimParens removes the parentheses enclosing the whole condition.
+func trimParens(sql string) string {
+	for {
+		sql = strings.TrimSpace(sql)
+		if len(sql) < 2 || sql[0] != '(' || sql[len(sql)-1] != ')' {
+			return sql
+		}
+
+		depth := 0
+		for i, c := range sql {
+			switch c {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth == 0 && i < len(sql)-1 {
+				// the first parenthesis is closed before the end, e.g. "(a) AND (b)"
+				return sql
+			}
+		}
+		sql = sql[1 : len(sql)-1]
+	}
+}
+
+// splitTopLevel splits the condition by the operator outside of parentheses.
+func splitTopLevel(sql, op string) []string {
+	upper := strings.ToUpper(sql)
+	sep := " " + op + " "
+
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(sql); i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(upper[i:], sep) {
+				parts = append(parts, sql[start:i])
+				start = i + len(sep)
+				i += len(sep) - 1
+			}
+		}
+	}
+	return append(parts, sql[start:])
+}
+
+// isAlwaysTrue returns true if the condition is empty or always true.
+func isAlwaysTrue(sql string) bool {
+	sql = trimParens(sql)
+	for _, cond := range alwaysTrueConditions {
+		if strings.EqualFold(strings.ReplaceAll(sql, " ", ""), cond) {
+			return true
+		}
+	}
+
+	if parts := splitTopLevel(sql, "OR"); len(parts) > 1 {
+		for _, p := range parts {
+			if isAlwaysTrue(p) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if parts := splitTopLevel(sql, "AND"); len(parts) > 1 {
+		for _, p := range parts {
+			if !isAlwaysTrue(p) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// checkFullTable returns ErrFullTable if the WHERE conditions and the joins do not filter the rows.
+// The version condition of WithVersion is not counted, because it matches all rows of the version.
+func checkFullTable(dialect Dialect, whereParts, joins []Sqlizer) error {
+	for _, p := range whereParts {
+		if wp, ok := p.(*wherePart); ok {
+			if _, ok := wp.pred.(versionCond); ok {
+				continue
+			}
+		}
+		sql, _, err := nestedToSql(p)
+		if err != nil {
+			return err
+		}
+		if !isAlwaysTrue(sql) {
+			return nil
+		}
+	}
+
+	// multi-table statements of MySQL and SQL Server join the target table,
+	// so the rows without the joined rows are not affected
+	if dialect == DialectMySQL || dialect == DialectSQLServer {
+		for _, join := range joins {
+			sql, _, err := nestedToSql(join)
+			if err != nil {
+				return err
+			}
+			if isFilteringJoin(sql) {
+				return nil
+			}
+		}
+	}
+	return ErrFullTable
+}
+
+// isFilteringJoin returns true if the join is an inner join with ON or USING clause.
+func isFilteringJoin(sql string) bool {
+	words := strings.Fields(strings.ToUpper(sql))
+	i := slices.Index(words, "JOIN")
+	if i < 0 {
+		return false
+	}
+	for _, kind := range words[:i] {
+		if kind == "LEFT" || kind == "RIGHT" || kind == "FULL" || kind == "CROSS" {
+			return false
+		}
+	}
+
+	if loc := joinOnRegexp.FindStringIndex(sql); loc != nil {
+		return !isAlwaysTrue(sql[loc[1]:])
+	}
+	return slices.Contains(words[i+1:], "USING")
+}
+
+// AllowFullTable allows the statement without WHERE conditions, which updates all rows of the table.
+//
+// See ErrFullTable for more information.
+func (b UpdateBuilder) AllowFullTable() UpdateBuilder {
+	return builder.Set(b, "AllowFullTable", true).(UpdateBuilder)
+}
+
+// AllowFullTable allows the statement without WHERE conditions, which deletes all rows of the table.
+//
+// See ErrFullTable for more information.
+func (b DeleteBuilder) AllowFullTable() DeleteBuilder {
+	return builder.Set(b, "AllowFullTable", true).(DeleteBuilder)
+}
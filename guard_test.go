@@ -0,0 +1,105 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullTableGuard(t *testing.T) {
+	t.Parallel()
+	for _, where := range []Sqlizer{
+		In("id", []int{}),
+		NotIn("id", []int{}),
+		EqNotEmpty{"id": nil, "name": ""},
+		Eq{},
+		And{EqNotEmpty{"id": 0}, Expr("1 = 1")},
+		Or{Eq{"id": 1}, Expr("TRUE")},
+		Range("id", nil, nil),
+	} {
+		_, _, err := Update("t").Set("a", 1).Where(where).ToSql()
+		require.ErrorIs(t, err, ErrFullTable, "%#v", where)
+
+		_, _, err = Delete("t").Where(where).ToSql()
+		require.ErrorIs(t, err, ErrFullTable, "%#v", where)
+	}
+
+	_, _, err := Update("t").Set("a", 1).ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+
+	_, _, err = Delete("t").ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+}
+
+func TestFullTableGuardFiltered(t *testing.T) {
+	t.Parallel()
+	for _, where := range []Sqlizer{
+		In("id", []int{1}),
+		Eq{"id": []int{}},
+		EqNotEmpty{"id": 1, "name": ""},
+		And{EqNotEmpty{"id": 0}, Expr("a = ?", 1)},
+		Expr("(a = 1) OR (b = 1)"),
+		Expr("(1=1) AND (a = 1)"),
+	} {
+		_, _, err := Delete("t").Where(where).ToSql()
+		require.NoError(t, err, "%#v", where)
+	}
+}
+
+func TestAllowFullTable(t *testing.T) {
+	t.Parallel()
+	sql, _, err := Update("t").Set("a", 1).AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ?", sql)
+
+	sql, _, err = Delete("t").AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+
+	sql, _, err = Update("t").FromValues("v", []string{"id"}, []map[string]any{{"id": 1, "a": 2}}).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = v.a FROM (VALUES (CAST(? AS bigint), CAST(? AS bigint))) AS v(id, a) "+
+		"WHERE t.id = v.id", sql)
+}
+
+func TestFullTableGuardOnlyDeleted(t *testing.T) {
+	t.Parallel()
+	b := StatementBuilder.SoftDelete("t", "deleted_at").Update("t").Set("deleted_at", nil)
+
+	sql, _, err := b.OnlyDeleted().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET deleted_at = ? WHERE deleted_at IS NOT NULL", sql)
+
+	_, _, err = b.ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+
+	_, _, err = b.WithDeleted().ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+}
+
+func TestFullTableGuardJoins(t *testing.T) {
+	t.Parallel()
+	sql, _, err := Update("t").Set("a", 1).Join("j ON j.id = t.j_id").Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t JOIN j ON j.id = t.j_id SET a = ?", sql)
+
+	sql, _, err = Update("t").Set("a", 1).From("t").Join("j ON j.id = t.j_id").Dialect(DialectSQLServer).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? FROM t JOIN j ON j.id = t.j_id", sql)
+
+	sql, _, err = Delete("t").Join("j USING (id)").Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE t FROM t JOIN j USING (id)", sql)
+
+	// outer joins and always true ON clause do not filter the rows
+	_, _, err = Update("t").Set("a", 1).LeftJoin("j ON j.id = t.j_id").Dialect(DialectMySQL).ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+
+	_, _, err = Delete("t").Join("j ON 1=1").Dialect(DialectMySQL).ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+
+	// PostgreSQL joins do not restrict the target table
+	_, _, err = Update("t").Set("a", 1).From("f").Join("j ON j.id = f.j_id").Dialect(DialectPostgres).ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+}
@@ -0,0 +1,205 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Hierarchy traversal helpers of tree tables (id, parent_id)
+// e.g.
+// WITH RECURSIVE descendants(id, parent_id, path, depth) AS (
+//   SELECT id, parent_id, '/1/', 0 FROM nodes WHERE id = 1
+//   UNION ALL
+//   SELECT n.id, n.parent_id, descendants.path || n.id || '/', descendants.depth + 1
+//   FROM nodes n JOIN descendants ON n.parent_id = descendants.id
+//   WHERE n.id is not in descendants.path
+// )
+// SELECT descendants.id, descendants.parent_id, descendants.depth, descendants.path FROM descendants
+
+// Default names of the hierarchy CTE columns.
+const (
+	defaultHierarchyDepthColumn = "depth"
+	defaultHierarchyPathColumn  = "path"
+)
+
+// HierarchyOption is used to specify how the tree is traversed by Descendants and Ancestors.
+type HierarchyOption struct {
+	// Name is the name of the CTE, "descendants" or "ancestors" if not set.
+	// The columns of the query are qualified by the name.
+	Name string
+	// MaxDepth limits the number of levels below (Descendants) or above (Ancestors) the start row.
+	// 0 means no limit.
+	MaxDepth uint64
+	// DepthColumn is the name of the depth column, "depth" if not set.
+	DepthColumn string
+	// PathColumn is the name of the path column, "path" if not set.
+	PathColumn string
+}
+
+// mergeHierarchyOptions merges the given options into one, the last non-empty value wins.
+func mergeHierarchyOptions(opts []HierarchyOption) HierarchyOption {
+	res := HierarchyOption{
+		Name:        "",
+		MaxDepth:    0,
+		DepthColumn: defaultHierarchyDepthColumn,
+		PathColumn:  defaultHierarchyPathColumn,
+	}
+	for _, opt := range opts {
+		if opt.Name != "" {
+			res.Name = opt.Name
+		}
+		if opt.MaxDepth > 0 {
+			res.MaxDepth = opt.MaxDepth
+		}
+		if opt.DepthColumn != "" {
+			res.DepthColumn = opt.DepthColumn
+		}
+		if opt.PathColumn != "" {
+			res.PathColumn = opt.PathColumn
+		}
+	}
+	return res
+}
+
+// hierarchyCte is the recursive CTE of the tree traversal. The path column is
+// built by the dialect specific string functions, so the CTE is rendered for the dialect.
+type hierarchyCte struct {
+	table     string
+	idCol     string
+	parentCol string
+	startID   any
+	ancestors bool
+	opt       HierarchyOption
+}
+
+// pathText casts the expression to the string type of the path column.
+func pathText(dialect Dialect, expr string) string {
+	var typ string
+	switch dialect {
+	case DialectMySQL:
+		typ = "CHAR(4000)"
+	case DialectSQLServer:
+		typ = "VARCHAR(MAX)"
+	case DialectOracle:
+		typ = "VARCHAR2(4000)"
+	case DialectPostgres, DialectSQLite, DialectUndefined:
+		typ = "TEXT"
+	}
+	return fmt.Sprintf("CAST(%s AS %s)", expr, typ)
+}
+
+// pathConcat concatenates the string expressions.
+func pathConcat(dialect Dialect, exprs ...string) string {
+	switch dialect {
+	case DialectMySQL:
+		return "CONCAT(" + strings.Join(exprs, ", ") + ")"
+	case DialectSQLServer:
+		return strings.Join(exprs, " + ")
+	case DialectPostgres, DialectSQLite, DialectOracle, DialectUndefined:
+	}
+	return strings.Join(exprs, " || ")
+}
+
+// pathNotContains returns the condition that the path does not contain the substring.
+func pathNotContains(dialect Dialect, path, sub string) string {
+	switch dialect {
+	case DialectMySQL:
+		return fmt.Sprintf("LOCATE(%s, %s) = 0", sub, path)
+	case DialectSQLServer:
+		return fmt.Sprintf("CHARINDEX(%s, %s) = 0", sub, path)
+	case DialectSQLite, DialectOracle:
+		return fmt.Sprintf("INSTR(%s, %s) = 0", path, sub)
+	case DialectPostgres, DialectUndefined:
+	}
+	return fmt.Sprintf("POSITION(%s IN %s) = 0", sub, path)
+}
+
+// recursiveCte returns the recursive CTE of the traversal for the dialect.
+func (h hierarchyCte) recursiveCte(dialect Dialect) RecursiveCteBuilder {
+	name := h.opt.Name
+	nodeID := pathText(dialect, "n."+h.idCol)
+	parentPath := name + "." + h.opt.PathColumn
+
+	// the path is "/id/.../id/" of the visited rows, a row already in the path means a cycle
+	anchorPath := pathText(dialect, pathConcat(dialect, "'/'", pathText(dialect, h.idCol), "'/'"))
+	recursivePath := pathText(dialect, pathConcat(dialect, parentPath, nodeID, "'/'"))
+	cycleCond := pathNotContains(dialect, parentPath, pathConcat(dialect, "'/'", nodeID, "'/'"))
+
+	joinCond := fmt.Sprintf("n.%s = %s.%s", h.parentCol, name, h.idCol)
+	if h.ancestors {
+		joinCond = fmt.Sprintf("n.%s = %s.%s", h.idCol, name, h.parentCol)
+	}
+
+	anchor := Select(h.idCol, h.parentCol, anchorPath).From(h.table).Where(Eq{h.idCol: h.startID})
+	recursive := Select("n."+h.idCol, "n."+h.parentCol, recursivePath).
+		From(h.table + " n").
+		Join(fmt.Sprintf("%s ON %s", name, joinCond)).
+		Where(cycleCond)
+
+	cte := RecursiveCte(name, anchor, recursive).
+		Columns(h.idCol, h.parentCol, h.opt.PathColumn).
+		Depth(h.opt.DepthColumn)
+	if h.opt.MaxDepth > 0 {
+		cte = cte.MaxDepth(h.opt.MaxDepth)
+	}
+	return cte
+}
+
+// ToSql builds the CTE "name(...) AS (...)" into a SQL string and bound args.
+func (h hierarchyCte) ToSql() (sql string, args []any, err error) {
+	return h.toSqlDialect(DialectUndefined)
+}
+
+func (h hierarchyCte) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	return h.recursiveCte(dialect).toSqlDialect(dialect)
+}
+
+// hierarchy returns SELECT of the traversal CTE.
+func hierarchy(cte hierarchyCte, opts []HierarchyOption, defaultName string) SelectBuilder {
+	cte.opt = mergeHierarchyOptions(opts)
+	if cte.opt.Name == "" {
+		cte.opt.Name = defaultName
+	}
+
+	name := cte.opt.Name
+	b := Select(
+		name+"."+cte.idCol,
+		name+"."+cte.parentCol,
+		name+"."+cte.opt.DepthColumn,
+		name+"."+cte.opt.PathColumn,
+	).From(name)
+
+	b = builder.Set(b, "RecursiveCtes", true).(SelectBuilder)
+	return builder.Append(b, "Ctes", cte).(SelectBuilder)
+}
+
+// Descendants returns SELECT of the row with rootID and all its descendants of the tree table,
+// where parentCol references idCol of the parent row. The query selects the id, parent, depth
+// (0 for the root row) and path ("/1/2/5/") columns of the recursive CTE named "descendants",
+// and can be filtered, ordered and joined as any other SELECT:
+//
+//	Descendants("categories", "id", "parent_id", 1, HierarchyOption{MaxDepth: 2}).
+//		Column("c.name").
+//		Join("categories c ON c.id = descendants.id").
+//		Where("descendants.depth > ?", 0).
+//		OrderBy("descendants.path")
+//
+// The rows already in the path are skipped, so the query is finite on cyclic data.
+// The path is built by the string functions of the query dialect.
+func Descendants(table, idCol, parentCol string, rootID any, opts ...HierarchyOption) SelectBuilder {
+	//nolint:exhaustruct // opt is set by hierarchy
+	cte := hierarchyCte{table: table, idCol: idCol, parentCol: parentCol, startID: rootID}
+	return hierarchy(cte, opts, "descendants")
+}
+
+// Ancestors returns SELECT of the row with id and all its ancestors of the tree table up to the root row.
+// The depth is 0 for the row with id, 1 for its parent and so on. The CTE is named "ancestors".
+//
+// See Descendants for more information.
+func Ancestors(table, idCol, parentCol string, id any, opts ...HierarchyOption) SelectBuilder {
+	//nolint:exhaustruct // opt is set by hierarchy
+	cte := hierarchyCte{table: table, idCol: idCol, parentCol: parentCol, startID: id, ancestors: true}
+	return hierarchy(cte, opts, "ancestors")
+}
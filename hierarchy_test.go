@@ -0,0 +1,90 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescendants(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Descendants("nodes", "id", "parent_id", 1, HierarchyOption{MaxDepth: 3}).
+		Column("c.name").
+		Join("categories c ON c.id = descendants.id").
+		Where("descendants.depth > ?", 0).
+		OrderBy("descendants.path").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE descendants(id, parent_id, path, depth) AS (" +
+		"SELECT id, parent_id, CAST('/' || CAST(id AS TEXT) || '/' AS TEXT), 0 AS depth FROM nodes WHERE id = $1 " +
+		"UNION ALL " +
+		"SELECT n.id, n.parent_id, CAST(descendants.path || CAST(n.id AS TEXT) || '/' AS TEXT), " +
+		"descendants.depth + 1 AS depth " +
+		"FROM nodes n JOIN descendants ON n.parent_id = descendants.id " +
+		"WHERE POSITION('/' || CAST(n.id AS TEXT) || '/' IN descendants.path) = 0 AND descendants.depth < $2) " +
+		"SELECT descendants.id, descendants.parent_id, descendants.depth, descendants.path, c.name " +
+		"FROM descendants JOIN categories c ON c.id = descendants.id " +
+		"WHERE descendants.depth > $3 ORDER BY descendants.path"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, uint64(3), 0}, args)
+}
+
+func TestAncestors(t *testing.T) {
+	t.Parallel()
+	opt := HierarchyOption{Name: "up", DepthColumn: "lvl", PathColumn: "trail"}
+	sql, args, err := Ancestors("nodes", "id", "parent_id", 5, opt).ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE up(id, parent_id, trail, lvl) AS (" +
+		"SELECT id, parent_id, CAST('/' || CAST(id AS TEXT) || '/' AS TEXT), 0 AS lvl FROM nodes WHERE id = ? " +
+		"UNION ALL " +
+		"SELECT n.id, n.parent_id, CAST(up.trail || CAST(n.id AS TEXT) || '/' AS TEXT), up.lvl + 1 AS lvl " +
+		"FROM nodes n JOIN up ON n.id = up.parent_id " +
+		"WHERE POSITION('/' || CAST(n.id AS TEXT) || '/' IN up.trail) = 0) " +
+		"SELECT up.id, up.parent_id, up.lvl, up.trail FROM up"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{5}, args)
+}
+
+func TestHierarchyDialects(t *testing.T) {
+	t.Parallel()
+	q := Descendants("nodes", "id", "parent_id", 1)
+
+	sql, _, err := q.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WITH RECURSIVE descendants(")
+	assert.Contains(t, sql, "CAST(CONCAT(descendants.path, CAST(n.id AS CHAR(4000)), '/') AS CHAR(4000))")
+	assert.Contains(t, sql, "LOCATE(CONCAT('/', CAST(n.id AS CHAR(4000)), '/'), descendants.path) = 0")
+
+	sql, _, err = q.PlaceholderFormat(AtP).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WITH descendants(")
+	assert.Contains(t, sql, "CHARINDEX('/' + CAST(n.id AS VARCHAR(MAX)) + '/', descendants.path) = 0")
+
+	sql, _, err = q.PlaceholderFormat(Colon).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WITH descendants(")
+	assert.Contains(t, sql, "INSTR(descendants.path, '/' || CAST(n.id AS VARCHAR2(4000)) || '/') = 0")
+
+	sql, _, err = q.Dialect(DialectSQLite).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "INSTR(descendants.path, '/' || CAST(n.id AS TEXT) || '/') = 0")
+}
+
+func TestHierarchySubquery(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Select("name").
+		From("nodes").
+		Where(Expr("id IN (?)", Descendants("nodes", "id", "parent_id", 1).RemoveColumns().Column("descendants.id"))).
+		Where("active = ?", true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WHERE id IN (WITH RECURSIVE descendants(")
+	assert.Contains(t, sql, "FROM nodes WHERE id = $1 UNION ALL")
+	assert.Contains(t, sql, ") SELECT descendants.id FROM descendants) AND active = $2")
+	assert.Equal(t, []any{1, true}, args)
+}
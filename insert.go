@@ -13,6 +13,7 @@ import (
 
 type insertData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	Prefixes          []Sqlizer
 	StatementKeyword  string
 	Options           []string
@@ -21,29 +22,39 @@ type insertData struct {
 	Values            [][]any
 	Suffixes          []Sqlizer
 	Select            *SelectBuilder
+	Unnest            bool // values are bound as one array per column
+	DefaultValues     bool
+	UnnestTypes       []string
+
+	ConflictTarget      []string
+	ConflictConstraint  string
+	ConflictTargetWhere []Sqlizer
+	ConflictAction      conflictAction
+	ConflictSetClauses  []setClause
+	ConflictWhereParts  []Sqlizer
+	DuplicateKey        bool // MySQL flavored conflict API is used
+	RowAlias            string
+
+	Returning []any
+
+	Err error // error of the builder methods (e.g. Struct), returned by ToSql
 }
 
-func (d *insertData) toSqlRaw() (sqlStr string, args []any, err error) {
-	if d.Into == "" {
-		err = errors.New("insert statements must specify a table")
-		return "", nil, err
-	}
-	if len(d.Values) == 0 && d.Select == nil {
-		err = errors.New("insert statements must have at least one set of values or select clause")
-		return "", nil, err
+func (d *insertData) writePrefixes(sql *bytes.Buffer, args []any) ([]any, error) {
+	if len(d.Prefixes) == 0 {
+		return args, nil
 	}
 
-	sql := &bytes.Buffer{}
-
-	if len(d.Prefixes) > 0 {
-		args, err = appendToSql(d.Prefixes, sql, " ", args)
-		if err != nil {
-			return "", nil, err
-		}
-
-		sql.WriteString(" ")
+	args, err := appendToSql(d.Prefixes, sql, " ", args)
+	if err != nil {
+		return nil, err
 	}
 
+	_, _ = sql.WriteString(" ")
+	return args, nil
+}
+
+func (d *insertData) writeInsertClause(sql *bytes.Buffer, dialect Dialect) {
 	if d.StatementKeyword == "" {
 		_, _ = sql.WriteString("INSERT ")
 	} else {
@@ -51,6 +62,10 @@ func (d *insertData) toSqlRaw() (sqlStr string, args []any, err error) {
 		_, _ = sql.WriteString(" ")
 	}
 
+	if dialect == DialectMySQL && d.ConflictAction == conflictActionNothing {
+		_, _ = sql.WriteString("IGNORE ")
+	}
+
 	if len(d.Options) > 0 {
 		_, _ = sql.WriteString(strings.Join(d.Options, " "))
 		_, _ = sql.WriteString(" ")
@@ -58,31 +73,130 @@ func (d *insertData) toSqlRaw() (sqlStr string, args []any, err error) {
 
 	_, _ = sql.WriteString("INTO ")
 	_, _ = sql.WriteString(d.Into)
-	_, _ = sql.WriteString(" ")
 
 	if len(d.Columns) > 0 {
-		_, _ = sql.WriteString("(")
+		_, _ = sql.WriteString(" (")
 		_, _ = sql.WriteString(strings.Join(d.Columns, ","))
-		_, _ = sql.WriteString(") ")
+		_, _ = sql.WriteString(")")
 	}
+}
+
+func (d *insertData) writeSource(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	_, _ = sql.WriteString(" ")
 
 	if d.Select != nil {
-		args, err = d.appendSelectToSQL(sql, args)
-	} else {
-		args, err = d.appendValuesToSQL(sql, args)
+		return d.appendSelectToSQL(sql, args)
+	}
+
+	if d.Unnest {
+		return d.appendUnnestToSQL(sql, args)
+	}
+
+	var err error
+	if d.DefaultValues {
+		d.writeDefaultValues(sql, dialect)
+	} else if args, err = d.appendValuesToSQL(sql, args); err != nil {
+		return nil, err
+	}
+
+	if d.RowAlias != "" {
+		_, _ = sql.WriteString(" AS ")
+		_, _ = sql.WriteString(d.RowAlias)
+	}
+
+	return args, nil
+}
+
+// checkDefaultValues checks that DEFAULT VALUES clause can be used.
+func (d *insertData) checkDefaultValues(dialect Dialect) error {
+	if !d.DefaultValues {
+		return nil
+	}
+	if dialect == DialectOracle {
+		return errUnsupported("DEFAULT VALUES", dialect)
+	}
+	if len(d.Columns) > 0 || len(d.Values) > 0 || d.Select != nil || d.Unnest {
+		return errors.New("default values cannot be used with columns, values or select clause")
+	}
+	return nil
+}
+
+func (d *insertData) writeDefaultValues(sql *bytes.Buffer, dialect Dialect) {
+	if dialect == DialectMySQL {
+		_, _ = sql.WriteString("() VALUES ()")
+		return
+	}
+	_, _ = sql.WriteString("DEFAULT VALUES")
+}
+
+func (d *insertData) writeSuffixes(sql *bytes.Buffer, args []any) ([]any, error) {
+	if len(d.Suffixes) == 0 {
+		return args, nil
+	}
+
+	_, _ = sql.WriteString(" ")
+	return appendToSql(d.Suffixes, sql, " ", args)
+}
+
+func (d *insertData) toSqlRaw() (sqlStr string, args []any, err error) {
+	if d.Err != nil {
+		return "", nil, d.Err
+	}
+	if d.Into == "" {
+		err = errors.New("insert statements must specify a table")
+		return "", nil, err
+	}
+	if len(d.Values) == 0 && d.Select == nil && !d.DefaultValues {
+		err = errors.New("insert statements must have at least one set of values or select clause")
+		return "", nil, err
 	}
+
+	dialect, err := d.conflictDialect()
 	if err != nil {
 		return "", nil, err
 	}
+	if err = checkReturning(dialect, d.Returning); err != nil {
+		return "", nil, err
+	}
+	if err = d.checkUnnest(dialect); err != nil {
+		return "", nil, err
+	}
+	if err = d.checkDefaultValues(dialect); err != nil {
+		return "", nil, err
+	}
+
+	sql := &bytes.Buffer{}
+
+	if args, err = d.writePrefixes(sql, args); err != nil {
+		return "", nil, err
+	}
+
+	d.writeInsertClause(sql, dialect)
+
+	if dialect == DialectSQLServer {
+		if args, err = writeReturning(sql, d.Returning, dialect, outputInserted, args); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if args, err = d.writeSource(sql, dialect, args); err != nil {
+		return "", nil, err
+	}
+
+	if args, err = d.writeOnConflict(sql, dialect, args); err != nil {
+		return "", nil, err
+	}
 
-	if len(d.Suffixes) > 0 {
-		sql.WriteString(" ")
-		args, err = appendToSql(d.Suffixes, sql, " ", args)
-		if err != nil {
+	if dialect != DialectSQLServer {
+		if args, err = writeReturning(sql, d.Returning, dialect, outputInserted, args); err != nil {
 			return "", nil, err
 		}
 	}
 
+	if args, err = d.writeSuffixes(sql, args); err != nil {
+		return "", nil, err
+	}
+
 	return sql.String(), args, nil
 }
 
@@ -95,11 +209,29 @@ func (d *insertData) ToSql() (sqlStr string, args []any, err error) {
 	return sqlStr, a, err
 }
 
+// validateValues checks that all rows have the same number of values as columns.
+func (d *insertData) validateValues() error {
+	width := len(d.Columns)
+	if width == 0 {
+		width = len(d.Values[0])
+	}
+	for i, row := range d.Values {
+		if len(row) != width {
+			return fmt.Errorf("insert row %d has %d values, expected %d", i, len(row), width)
+		}
+	}
+	return nil
+}
+
 func (d *insertData) appendValuesToSQL(w io.Writer, args []any) ([]any, error) {
 	if len(d.Values) == 0 {
 		return args, errors.New("values for insert statements are not set")
 	}
 
+	if err := d.validateValues(); err != nil {
+		return nil, err
+	}
+
 	_, _ = io.WriteString(w, "VALUES ")
 
 	valuesStrings := make([]string, len(d.Values))
@@ -159,6 +291,12 @@ func (b InsertBuilder) PlaceholderFormat(f PlaceholderFormat) InsertBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(InsertBuilder)
 }
 
+// Dialect sets the SQL dialect of the query. If not set, the dialect is
+// detected by the PlaceholderFormat.
+func (b InsertBuilder) Dialect(d Dialect) InsertBuilder {
+	return builder.Set(b, "Dialect", d).(InsertBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -239,12 +377,66 @@ func (b InsertBuilder) SetMap(clauses map[string]any) InsertBuilder {
 	return b
 }
 
+// SetMaps sets columns and values for multi-row insert builder from a slice of maps of column name and value.
+// Columns are the sorted union of the keys of all rows, the missing values are
+// set to fill, e.g. Default or nil (NULL).
+// Note that it will reset all previous columns and values was set if any.
+func (b InsertBuilder) SetMaps(rows []map[string]any, fill any) InsertBuilder {
+	colSet := make(map[string]struct{})
+	for _, row := range rows {
+		for col := range row {
+			colSet[col] = struct{}{}
+		}
+	}
+
+	cols := make([]string, 0, len(colSet))
+	for col := range colSet {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		vals := make([]any, len(cols))
+		for j, col := range cols {
+			val, ok := row[col]
+			if !ok {
+				val = fill
+			}
+			vals[j] = val
+		}
+		values[i] = vals
+	}
+
+	b = builder.Set(b, "Columns", cols).(InsertBuilder)
+	b = builder.Set(b, "Values", values).(InsertBuilder)
+
+	return b
+}
+
 // Select set Select clause for insert query.
 // If Values and Select are used, then Select has higher priority.
 func (b InsertBuilder) Select(sb SelectBuilder) InsertBuilder {
 	return builder.Set(b, "Select", &sb).(InsertBuilder)
 }
 
+// DefaultValues makes the query to insert a row of the column defaults:
+// "INSERT INTO t DEFAULT VALUES" ("INSERT INTO t () VALUES ()" for MySQL).
+// It cannot be combined with Columns, Values and Select.
+func (b InsertBuilder) DefaultValues() InsertBuilder {
+	return builder.Set(b, "DefaultValues", true).(InsertBuilder)
+}
+
+// Returning adds RETURNING clause columns to the query. Columns can be strings
+// or Sqlizers, e.g. "id" or New("name").
+//
+// For SQL Server OUTPUT clause is rendered instead, and plain column names are
+// qualified with "inserted", e.g. "OUTPUT inserted.id". RETURNING is not supported by
+// MySQL and Oracle dialects.
+func (b InsertBuilder) Returning(columns ...any) InsertBuilder {
+	return builder.Extend(b, "Returning", columns).(InsertBuilder)
+}
+
 func (b InsertBuilder) statementKeyword(keyword string) InsertBuilder {
 	return builder.Set(b, "StatementKeyword", keyword).(InsertBuilder)
 }
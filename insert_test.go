@@ -138,3 +138,79 @@ func TestInsertValuesNestedSelect_DollarPlaceholderNumberingConflict(t *testing.
 	assert.Equal(t, expectedSQL, sql)
 	assert.Equal(t, []any{7, 8}, args)
 }
+
+func TestInsertBuilderDefaultValues(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("t").DefaultValues().Returning("id").ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t DEFAULT VALUES RETURNING id", sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Insert("t").DefaultValues().Returning("id").PlaceholderFormat(AtP).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t OUTPUT inserted.id DEFAULT VALUES", sql)
+
+	sql, _, err = Insert("t").DefaultValues().Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t () VALUES ()", sql)
+
+	_, _, err = Insert("t").Columns("a").DefaultValues().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Values(1).DefaultValues().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").DefaultValues().Dialect(DialectOracle).ToSql()
+	require.EqualError(t, err, "DEFAULT VALUES is not supported by the oracle dialect")
+}
+
+func TestInsertBuilderDefault(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("t").Columns("id", "created_at").
+		Values(1, Default).
+		Values(2, "2024-01-01").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id,created_at) VALUES ($1,DEFAULT),($2,$3)", sql)
+	assert.Equal(t, []any{1, 2, "2024-01-01"}, args)
+
+	sql, args, err = Insert("t").SetMap(map[string]any{"id": 1, "created_at": Default}).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (created_at,id) VALUES (DEFAULT,?)", sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = Update("t").Set("created_at", Default).Where("id = ?", 1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET created_at = DEFAULT WHERE id = ?", sql)
+}
+
+func TestInsertBuilderSetMaps(t *testing.T) {
+	t.Parallel()
+	rows := []map[string]any{
+		{"id": 1, "name": "moe"},
+		{"id": 2, "age": 30},
+	}
+
+	sql, args, err := Insert("users").SetMaps(rows, Default).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (age,id,name) VALUES (DEFAULT,?,?),(?,?,DEFAULT)", sql)
+	assert.Equal(t, []any{1, "moe", 30, 2}, args)
+
+	sql, args, err = Insert("users").SetMaps(rows, nil).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (age,id,name) VALUES (?,?,?),(?,?,?)", sql)
+	assert.Equal(t, []any{nil, 1, "moe", 30, 2, nil}, args)
+
+	_, _, err = Insert("users").SetMaps(nil, nil).ToSql()
+	require.Error(t, err)
+}
+
+func TestInsertBuilderRowWidthErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Insert("users").Columns("id", "name").Values(1, "moe").Values(2).ToSql()
+	require.EqualError(t, err, "insert row 1 has 1 values, expected 2")
+
+	_, _, err = Insert("users").Values(1, "moe").Values(2).ToSql()
+	require.Error(t, err)
+}
@@ -455,3 +455,89 @@ INSERT INTO user_groups_all (user_id, group_id) VALUES
 	assert.Equal(t, []int64{1}, recursiveIDs)
 	assert.Equal(t, []string{"Engineering"}, recursiveNames)
 }
+
+func TestRecursiveCte(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE tree_nodes (
+	id bigint PRIMARY KEY,
+	parent_id bigint
+);
+INSERT INTO tree_nodes (id, parent_id) VALUES (1, NULL), (2, 1), (3, 1), (4, 2), (5, 4);
+UPDATE tree_nodes SET parent_id = 5 WHERE id = 1;
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	tree := sq.RecursiveCte("tree",
+		sq.Select("id").From("tree_nodes").Where(sq.Eq{"id": 1}),
+		sq.Select("n.id").From("tree_nodes n").Join("tree ON n.parent_id = tree.id"),
+	).Columns("id")
+
+	cycleQuery := sq.Select("id").
+		WithRecursive(tree.SearchDepthFirst("ord", "id").Cycle("is_cycle", "path", "id")).
+		From("tree").
+		Where("NOT is_cycle").
+		OrderBy("ord").
+		PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, cycleQuery)
+	assert.Equal(t, []int64{1, 2, 4, 5, 3}, ids)
+
+	depthQuery := sq.Select("id").
+		WithRecursive(tree.MaxDepth(2)).
+		From("tree").
+		Where("depth > ?", 0).
+		OrderBy("id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids = queryInt64s(t, pool, ctx, depthQuery)
+	assert.Equal(t, []int64{2, 3, 4}, ids)
+}
+
+func TestHierarchy(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE hierarchy_nodes (
+	id bigint PRIMARY KEY,
+	parent_id bigint,
+	name text NOT NULL
+);
+INSERT INTO hierarchy_nodes (id, parent_id, name) VALUES
+	(1, NULL, 'root'), (2, 1, 'a'), (3, 1, 'b'), (4, 2, 'a1'), (5, 4, 'a11'), (6, 6, 'self');
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	descendants := sq.Descendants("hierarchy_nodes", "id", "parent_id", 1, sq.HierarchyOption{MaxDepth: 2}).
+		RemoveColumns().
+		Columns("descendants.id", "h.name").
+		Join("hierarchy_nodes h ON h.id = descendants.id").
+		Where("descendants.depth > ?", 0).
+		OrderBy("descendants.id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx, descendants)
+	assert.Equal(t, []int64{2, 3, 4}, ids)
+	assert.Equal(t, []string{"a", "b", "a1"}, names)
+
+	ancestors := sq.Ancestors("hierarchy_nodes", "id", "parent_id", 5).
+		RemoveColumns().
+		Columns("id", "path").
+		OrderBy("depth").
+		PlaceholderFormat(sq.Dollar)
+
+	ids, paths := queryInt64StringPairs(t, pool, ctx, ancestors)
+	assert.Equal(t, []int64{5, 4, 2, 1}, ids)
+	assert.Equal(t, "/5/4/2/1/", paths[3])
+
+	cycle := sq.Descendants("hierarchy_nodes", "id", "parent_id", 6).
+		RemoveColumns().
+		Column("id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids = queryInt64s(t, pool, ctx, cycle)
+	assert.Equal(t, []int64{6}, ids)
+}
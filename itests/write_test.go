@@ -3,10 +3,12 @@
 package itests
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/georgysavva/scany/v2/pgxscan"
 	sq "github.com/n-r-w/squirrel"
+	"github.com/n-r-w/squirrel/pgxcopy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -186,3 +188,416 @@ CREATE TABLE archived_products (
 	assert.Equal(t, "Refill", mapped.Name)
 	assert.Equal(t, 7, mapped.Stock)
 }
+
+func TestReturning(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE returning_items (
+	id bigserial PRIMARY KEY,
+	name text NOT NULL,
+	qty integer NOT NULL
+);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	insert := sq.Insert("returning_items").
+		Columns("name", "qty").
+		Values("first", 1).
+		Values("second", 2).
+		Returning("id", "name").
+		PlaceholderFormat(sq.Dollar)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx, insert)
+	require.Len(t, ids, 2)
+	assert.ElementsMatch(t, []string{"first", "second"}, names)
+
+	update := sq.Update("returning_items").
+		Set("name", sq.Expr("name || ?", " renamed")).
+		Where(sq.Eq{"qty": 2}).
+		Returning("id", "name").
+		PlaceholderFormat(sq.Dollar)
+
+	_, names = queryInt64StringPairs(t, pool, ctx, update)
+	assert.Equal(t, []string{"second renamed"}, names)
+
+	upsert := sq.Insert("returning_items").
+		Columns("id", "name", "qty").
+		Values(ids[0], "upserted", 1).
+		OnConflict("id").
+		DoUpdate().
+		SetExcluded("name").
+		End().
+		Returning("id", "name").
+		PlaceholderFormat(sq.Dollar)
+
+	_, names = queryInt64StringPairs(t, pool, ctx, upsert)
+	assert.Equal(t, []string{"upserted"}, names)
+
+	del := sq.Delete("returning_items").
+		Where(sq.Eq{"qty": 1}).
+		Returning("id").
+		PlaceholderFormat(sq.Dollar)
+
+	deleted := queryInt64s(t, pool, ctx, del)
+	assert.Equal(t, []int64{ids[0]}, deleted)
+}
+
+func TestInsertOnConflict(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE counters (
+	id bigint PRIMARY KEY,
+	name text NOT NULL,
+	hits integer NOT NULL,
+	locked boolean NOT NULL DEFAULT false
+);
+INSERT INTO counters (id, name, hits, locked) VALUES (1, 'first', 1, false), (2, 'second', 1, true);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	upsert := sq.Insert("counters").
+		Columns("id", "name", "hits").
+		Values(1, "first renamed", 1).
+		Values(2, "second renamed", 1).
+		Values(3, "third", 1).
+		OnConflict("id").
+		DoUpdate().
+		SetExcluded("name").
+		Set("hits", sq.Expr("counters.hits + EXCLUDED.hits")).
+		Where("NOT counters.locked").
+		End().
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, upsert)
+	assert.ElementsMatch(t, []int64{1, 3}, ids)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "name").From("counters").OrderBy("id").PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, []string{"first renamed", "second", "third"}, names)
+
+	var hits int
+	err := pgxscan.Get(ctx, pool, &hits, "SELECT hits FROM counters WHERE id = 1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+
+	ignore := sq.Insert("counters").
+		Columns("id", "name", "hits").
+		Values(3, "ignored", 1).
+		OnConflict().
+		DoNothing().
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := ignore.ToSql()
+	require.NoError(t, err)
+
+	tag, err := pool.Exec(ctx, sql, args...)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), tag.RowsAffected())
+}
+
+func TestInsertUnnest(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE unnest_items (
+	id bigint PRIMARY KEY,
+	name text NOT NULL,
+	note text
+);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	note := "second"
+	insert := sq.Insert("unnest_items").
+		Columns("id", "name", "note").
+		Values(1, "first", nil).
+		Values(2, "second", &note).
+		Values(3, "third", nil).
+		Unnest().
+		Returning("id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, insert)
+	assert.ElementsMatch(t, []int64{1, 2, 3}, ids)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "name").From("unnest_items").Where(sq.NotEq{"note": nil}).PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{2}, ids)
+	assert.Equal(t, []string{"second"}, names)
+}
+
+func TestCopyFrom(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE copy_items (
+	id bigint PRIMARY KEY,
+	name text NOT NULL
+);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	type item struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+
+	n, err := pgxcopy.CopyFrom(ctx, pool, sq.Insert("copy_items").Structs([]item{{1, "first"}, {2, "second"}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	seq := func(yield func([]any) bool) {
+		for i := int64(3); i <= 1000; i++ {
+			if !yield([]any{i, fmt.Sprintf("item %d", i)}) {
+				return
+			}
+		}
+	}
+	n, err = pgxcopy.CopyFromSeq(ctx, pool, sq.Insert("copy_items").Columns("id", "name"), seq)
+	require.NoError(t, err)
+	assert.Equal(t, int64(998), n)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "name").From("copy_items").Where("id < ?", 4).OrderBy("id").PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, []string{"first", "second", "item 3"}, names)
+}
+
+func TestUpdateFromValues(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE bulk_items (
+	id bigint PRIMARY KEY,
+	name text NOT NULL,
+	qty integer NOT NULL
+);
+INSERT INTO bulk_items (id, name, qty) VALUES (1, 'first', 1), (2, 'second', 2), (3, 'third', 3);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	update := sq.Update("bulk_items").
+		FromValues("v", []string{"id"}, []map[string]any{
+			{"id": 1, "name": "first renamed"},
+			{"id": 3, "name": "third renamed"},
+		}).
+		Returning("bulk_items.id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, update)
+	assert.ElementsMatch(t, []int64{1, 3}, ids)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "name").From("bulk_items").OrderBy("id").PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.Equal(t, []string{"first renamed", "second", "third renamed"}, names)
+}
+
+func TestUpdateJSONPath(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE json_docs (
+	id bigint PRIMARY KEY,
+	doc jsonb NOT NULL
+);
+INSERT INTO json_docs (id, doc) VALUES (1, '{"a": {"b": 1}, "tags": ["x", "y"], "old": true}');
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	update := sq.Update("json_docs").
+		SetJSONPath("doc", []string{"a", "b"}, 2).
+		RemoveJSONPath("doc", []string{"tags", "0"}).
+		RemoveJSONPath("doc", []string{"old"}).
+		MergeJSON("doc", map[string]string{"name": "first"}).
+		Where("id = ?", 1).
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := update.ToSql()
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, sql, args...)
+	require.NoError(t, err)
+
+	var doc string
+	err = pgxscan.Get(ctx, pool, &doc, "SELECT doc::text FROM json_docs WHERE id = 1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": {"b": 2}, "tags": ["y"], "name": "first"}`, doc)
+}
+
+func TestUpdateJSONPathMissingParent(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE json_parent_docs (
+	id bigint PRIMARY KEY,
+	doc jsonb
+);
+INSERT INTO json_parent_docs (id, doc) VALUES (1, '{}'), (2, NULL), (3, '{"a": {}}');
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	update := sq.Update("json_parent_docs").
+		SetJSONPath("doc", []string{"a", "b"}, 1).
+		AllowFullTable().
+		PlaceholderFormat(sq.Dollar)
+
+	sql, args, err := update.ToSql()
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, sql, args...)
+	require.NoError(t, err)
+
+	// the documented limitation: NULL column and missing parent objects are not created
+	ids, docs := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "COALESCE(doc::text, 'null') AS name").From("json_parent_docs").OrderBy("id").
+			PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.JSONEq(t, `{}`, docs[0])
+	assert.JSONEq(t, `null`, docs[1])
+	assert.JSONEq(t, `{"a": {"b": 1}}`, docs[2])
+}
+
+func TestDeleteUpdateLimit(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE purge_items (
+	id bigint PRIMARY KEY,
+	state text NOT NULL
+);
+INSERT INTO purge_items (id, state) VALUES (1, 'old'), (2, 'old'), (3, 'old'), (4, 'new');
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	update := sq.Update("purge_items").
+		Set("state", "expired").
+		Where(sq.Eq{"state": "old"}).
+		OrderBy("id DESC").
+		Limit(1).
+		Returning("id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, update)
+	assert.Equal(t, []int64{3}, ids)
+
+	purge := sq.Delete("purge_items").
+		Where(sq.Eq{"state": "old"}).
+		OrderBy("id").
+		Limit(1).
+		LimitKey("id").
+		Returning("id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids = queryInt64s(t, pool, ctx, purge)
+	assert.Equal(t, []int64{1}, ids)
+
+	ids = queryInt64s(t, pool, ctx, purge)
+	assert.Equal(t, []int64{2}, ids)
+
+	ids = queryInt64s(t, pool, ctx, purge)
+	assert.Empty(t, ids)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE merge_items (
+	id bigint PRIMARY KEY,
+	name text NOT NULL
+);
+INSERT INTO merge_items (id, name) VALUES (1, 'first'), (2, 'second');
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	merge := sq.Merge("merge_items t").
+		UsingValues(sq.Values([][]any{{1, "first renamed", false}, {2, "", true}, {3, "third", false}}).
+			As("v", "id", "name", "deleted").
+			InferTypes()).
+		On("t.id = v.id").
+		WhenMatchedDelete(sq.Expr("v.deleted")).
+		WhenMatchedUpdate(nil, map[string]any{"name": sq.Expr("v.name")}).
+		WhenNotMatchedInsert(nil, map[string]any{"id": sq.Expr("v.id"), "name": sq.Expr("v.name")}).
+		Returning("v.id").
+		PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, merge)
+	assert.ElementsMatch(t, []int64{1, 2, 3}, ids)
+
+	ids, names := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "name").From("merge_items").OrderBy("id").PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1, 3}, ids)
+	assert.Equal(t, []string{"first renamed", "third"}, names)
+}
+
+func TestDataModifyingCte(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE cte_active (
+	id bigint PRIMARY KEY,
+	archived boolean NOT NULL
+);
+CREATE TABLE cte_archive (
+	id bigint PRIMARY KEY
+);
+INSERT INTO cte_active (id, archived) VALUES (1, false), (2, true), (3, true);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	move := sq.With("moved").As(
+		sq.Delete("cte_active").Where(sq.Eq{"archived": true}).Returning("id"),
+	).Insert(
+		sq.Insert("cte_archive").Select(sq.Select("id").From("moved").Where("id > ?", 2)).Returning("id"),
+	).PlaceholderFormat(sq.Dollar)
+
+	ids := queryInt64s(t, pool, ctx, move)
+	assert.Equal(t, []int64{3}, ids)
+
+	ids = queryInt64s(t, pool, ctx, sq.Select("id").From("cte_active").OrderBy("id").PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Parallel()
+
+	pool, ctx := newTestPool(t)
+	setupSQL := `
+CREATE TABLE patch_docs (
+	id bigint PRIMARY KEY,
+	doc jsonb
+);
+INSERT INTO patch_docs (id, doc) VALUES (1, '{"keep": 1, "drop": 2}'), (2, NULL);
+`
+	execSetup(t, pool, ctx, setupSQL)
+
+	fields := map[string]sq.PatchField{"doc": {JSON: true}}
+	patch := []byte(`{"doc": {"drop": null, "a": {"b": {"c": 1}}, "empty": {}}}`)
+
+	for _, id := range []int64{1, 2} {
+		update := sq.Update("patch_docs").Where(sq.Eq{"id": id}).ApplyMergePatch(patch, fields)
+		sql, args, err := update.PlaceholderFormat(sq.Dollar).ToSql()
+		require.NoError(t, err)
+		_, err = pool.Exec(ctx, sql, args...)
+		require.NoError(t, err)
+	}
+
+	ids, docs := queryInt64StringPairs(t, pool, ctx,
+		sq.Select("id", "doc::text AS name").From("patch_docs").OrderBy("id").PlaceholderFormat(sq.Dollar))
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.JSONEq(t, `{"keep": 1, "a": {"b": {"c": 1}}, "empty": {}}`, docs[0])
+	assert.JSONEq(t, `{"a": {"b": {"c": 1}}, "empty": {}}`, docs[1])
+}
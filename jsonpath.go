@@ -0,0 +1,276 @@
+package squirrel
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// JSON document mutations in SET clauses of UPDATE statements.
+// Mutations of the same column are combined into one assignment, e.g. for PostgreSQL
+// UPDATE t SET doc = jsonb_set(doc #- CAST(? AS text[]), CAST(? AS text[]), CAST(? AS jsonb), true)
+// Paths and values are always passed as args.
+
+type jsonOpKind int
+
+const (
+	jsonOpSet jsonOpKind = iota
+	jsonOpRemove
+	jsonOpMerge
+	jsonOpMergePatch // RFC 7396 merge patch, value is json.RawMessage
+)
+
+// jsonOp is a single mutation of JSON document.
+type jsonOp struct {
+	kind  jsonOpKind
+	path  []string
+	value any
+}
+
+// jsonMutation is a chain of mutations applied to JSON column.
+type jsonMutation struct {
+	column string
+	ops    []jsonOp
+}
+
+// ToSql builds the expression in PostgreSQL syntax.
+func (m jsonMutation) ToSql() (sql string, args []any, err error) {
+	return m.toSqlDialect(DialectPostgres)
+}
+
+func (m jsonMutation) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	// build returns the expression of the operation applied to acc and the args of the whole expression
+	var build func(acc string, accArgs []any, op jsonOp) (string, []any, error)
+	switch dialect { //nolint:exhaustive // other dialects are not supported
+	case DialectPostgres, DialectUndefined:
+		build = buildPostgresJSONOp
+	case DialectMySQL:
+		build = func(acc string, accArgs []any, op jsonOp) (string, []any, error) {
+			return buildPathJSONOp(acc, accArgs, op, mysqlJSONFuncs)
+		}
+	case DialectSQLite:
+		build = func(acc string, accArgs []any, op jsonOp) (string, []any, error) {
+			return buildPathJSONOp(acc, accArgs, op, sqliteJSONFuncs)
+		}
+	default:
+		return "", nil, errUnsupported("JSON mutation", dialect)
+	}
+
+	sql = m.column
+	for _, op := range m.ops {
+		if sql, args, err = build(sql, args, op); err != nil {
+			return "", nil, err
+		}
+	}
+	return sql, args, nil
+}
+
+// marshalJSONValue marshals the value unless it is already a JSON document.
+func marshalJSONValue(value any) (string, error) {
+	if raw, ok := value.(json.RawMessage); ok {
+		return string(raw), nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON value: %w", err)
+	}
+	return string(data), nil
+}
+
+// postgresJSONPath returns text[] literal of the path, e.g. {"a","b"}.
+func postgresJSONPath(path []string) string {
+	elems := make([]string, len(path))
+	for i, p := range path {
+		p = strings.ReplaceAll(p, `\`, `\\`)
+		p = strings.ReplaceAll(p, `"`, `\"`)
+		elems[i] = `"` + p + `"`
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
+
+// jsonPathExpr returns MySQL and SQLite JSON path of the path, e.g. $."a"[0].
+func jsonPathExpr(path []string) string {
+	var sb strings.Builder
+	_, _ = sb.WriteString("$")
+	for _, p := range path {
+		if p != "" && strings.Trim(p, "0123456789") == "" {
+			_, _ = fmt.Fprintf(&sb, "[%s]", p)
+			continue
+		}
+		p = strings.ReplaceAll(p, `\`, `\\`)
+		p = strings.ReplaceAll(p, `"`, `\"`)
+		_, _ = fmt.Fprintf(&sb, `."%s"`, p)
+	}
+	return sb.String()
+}
+
+// postgresMergePatch applies RFC 7396 merge patch object to the target document.
+// The target is replaced by an empty object if it is NULL (e.g. NULL column or missing key),
+// and nested objects of the patch are merged into the target values of their keys,
+// which are referenced by the target expression, so its args are repeated.
+func postgresMergePatch(target string, targetArgs []any, patch json.RawMessage) (string, []any, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return "", nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sql := fmt.Sprintf("COALESCE(%s, CAST('{}' AS jsonb))", target)
+	args := slices.Clone(targetArgs)
+	for _, key := range keys {
+		value := fields[key]
+		path := postgresJSONPath([]string{key})
+
+		switch {
+		case isJSONNull(value):
+			sql = fmt.Sprintf("%s #- CAST(? AS text[])", sql)
+			args = append(args, path)
+		case isJSONObject(value):
+			nestedTarget := target
+			if strings.ContainsAny(target, " (") {
+				nestedTarget = "(" + target + ")"
+			}
+			nestedTarget += " -> CAST(? AS text)"
+			nestedArgs := append(slices.Clone(targetArgs), key)
+			nested, nestedArgs, err := postgresMergePatch(nestedTarget, nestedArgs, value)
+			if err != nil {
+				return "", nil, err
+			}
+			sql = fmt.Sprintf("jsonb_set(%s, CAST(? AS text[]), %s, true)", sql, nested)
+			args = append(append(args, path), nestedArgs...)
+		default:
+			sql = fmt.Sprintf("jsonb_set(%s, CAST(? AS text[]), CAST(? AS jsonb), true)", sql)
+			args = append(args, path, string(value))
+		}
+	}
+	return sql, args, nil
+}
+
+func buildPostgresJSONOp(acc string, accArgs []any, op jsonOp) (string, []any, error) {
+	switch op.kind {
+	case jsonOpMergePatch:
+		raw, _ := op.value.(json.RawMessage)
+		return postgresMergePatch(acc, accArgs, raw)
+	case jsonOpRemove:
+		return fmt.Sprintf("%s #- CAST(? AS text[])", acc), append(accArgs, postgresJSONPath(op.path)), nil
+	case jsonOpSet:
+		value, err := marshalJSONValue(op.value)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("jsonb_set(%s, CAST(? AS text[]), CAST(? AS jsonb), true)", acc),
+			append(accArgs, postgresJSONPath(op.path), value), nil
+	case jsonOpMerge:
+		value, err := marshalJSONValue(op.value)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s || CAST(? AS jsonb)", acc), append(accArgs, value), nil
+	}
+	return "", nil, fmt.Errorf("unknown JSON operation %d", op.kind)
+}
+
+// pathJSONFuncs are the JSON functions of the dialects with JSON path strings.
+type pathJSONFuncs struct {
+	set         string
+	remove      string
+	mergePatch  string
+	value       string // JSON value expression of the arg
+	emptyObject string
+}
+
+//nolint:gochecknoglobals // read-only function names of the dialects
+var (
+	mysqlJSONFuncs = pathJSONFuncs{
+		set:         "JSON_SET",
+		remove:      "JSON_REMOVE",
+		mergePatch:  "JSON_MERGE_PATCH",
+		value:       "CAST(? AS JSON)",
+		emptyObject: "JSON_OBJECT()",
+	}
+	sqliteJSONFuncs = pathJSONFuncs{
+		set:         "json_set",
+		remove:      "json_remove",
+		mergePatch:  "json_patch",
+		value:       "json(?)",
+		emptyObject: "'{}'",
+	}
+)
+
+func buildPathJSONOp(acc string, accArgs []any, op jsonOp, fn pathJSONFuncs) (string, []any, error) {
+	switch op.kind {
+	case jsonOpRemove:
+		return fmt.Sprintf("%s(%s, ?)", fn.remove, acc), append(accArgs, jsonPathExpr(op.path)), nil
+	case jsonOpSet:
+		value, err := marshalJSONValue(op.value)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s(%s, ?, %s)", fn.set, acc, fn.value), append(accArgs, jsonPathExpr(op.path), value), nil
+	case jsonOpMerge, jsonOpMergePatch:
+		value, err := marshalJSONValue(op.value)
+		if err != nil {
+			return "", nil, err
+		}
+		if op.kind == jsonOpMergePatch {
+			// merge patch functions return NULL for NULL document, RFC 7396 treats it as empty object
+			acc = fmt.Sprintf("COALESCE(%s, %s)", acc, fn.emptyObject)
+		}
+		return fmt.Sprintf("%s(%s, %s)", fn.mergePatch, acc, fn.value), append(accArgs, value), nil
+	}
+	return "", nil, fmt.Errorf("unknown JSON operation %d", op.kind)
+}
+
+// combine appends the operations of the next JSON mutation of the same column.
+func (m jsonMutation) combine(next columnMutation) (columnMutation, bool) {
+	n, ok := next.(jsonMutation)
+	if !ok {
+		return nil, false
+	}
+	return jsonMutation{column: m.column, ops: append(append([]jsonOp{}, m.ops...), n.ops...)}, true
+}
+
+func (b UpdateBuilder) setJSONOp(column string, op jsonOp) UpdateBuilder {
+	return b.Set(column, jsonMutation{column: column, ops: []jsonOp{op}})
+}
+
+// SetJSONPath sets the value at the path of JSON document column. The value is marshalled
+// with encoding/json unless it is json.RawMessage. Numeric path elements are array indexes.
+//
+// Supported dialects are PostgreSQL (jsonb_set), MySQL (JSON_SET) and SQLite (json_set).
+// Several JSON mutations of the same column are combined into one assignment.
+//
+// The document is not created: the value is not set if the column is NULL
+// (jsonb_set and JSON_SET return NULL) or if a parent object of the path is missing,
+// e.g. path {"a", "b"} does not change {} document. Use ApplyMergePatch to create
+// missing objects, or set the column to a document with the parents first.
+func (b UpdateBuilder) SetJSONPath(column string, path []string, value any) UpdateBuilder {
+	return b.setJSONOp(column, jsonOp{kind: jsonOpSet, path: path, value: value})
+}
+
+// RemoveJSONPath removes the value at the path of JSON document column
+// (#- operator for PostgreSQL, JSON_REMOVE for MySQL, json_remove for SQLite).
+//
+// See SetJSONPath for more information.
+func (b UpdateBuilder) RemoveJSONPath(column string, path []string) UpdateBuilder {
+	return b.setJSONOp(column, jsonOp{kind: jsonOpRemove, path: path, value: nil})
+}
+
+// MergeJSON merges the patch into JSON document column. The patch is marshalled
+// with encoding/json unless it is json.RawMessage.
+//
+// PostgreSQL uses || operator, which replaces top-level keys, MySQL and SQLite use
+// JSON_MERGE_PATCH and json_patch (RFC 7396), which merge nested objects and remove null values.
+//
+// See SetJSONPath for more information.
+func (b UpdateBuilder) MergeJSON(column string, patch any) UpdateBuilder {
+	return b.setJSONOp(column, jsonOp{kind: jsonOpMerge, path: nil, value: patch})
+}
@@ -0,0 +1,68 @@
+package squirrel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateJSONPath(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Update("docs").
+		SetJSONPath("doc", []string{"a", "b"}, map[string]int{"x": 1}).
+		Set("updated_at", Now()).
+		RemoveJSONPath("doc", []string{"tags", "0"}).
+		MergeJSON("meta", json.RawMessage(`{"v":2}`)).
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE docs SET "+
+		"doc = jsonb_set(doc, CAST($1 AS text[]), CAST($2 AS jsonb), true) #- CAST($3 AS text[]), "+
+		"updated_at = now(), meta = meta || CAST($4 AS jsonb) WHERE id = $5", sql)
+	assert.Equal(t, []any{`{"a","b"}`, `{"x":1}`, `{"tags","0"}`, `{"v":2}`, 1}, args)
+}
+
+func TestUpdateJSONPathMySQL(t *testing.T) {
+	t.Parallel()
+	b := Update("docs").
+		SetJSONPath("doc", []string{"a", `b"c`}, "v").
+		RemoveJSONPath("doc", []string{"tags", "0"}).
+		MergeJSON("doc", map[string]any{"k": nil}).
+		AllowFullTable()
+
+	sql, args, err := b.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE docs SET doc = JSON_MERGE_PATCH(JSON_REMOVE(JSON_SET(doc, ?, CAST(? AS JSON)), ?), "+
+		"CAST(? AS JSON))", sql)
+	assert.Equal(t, []any{`$."a"."b\"c"`, `"v"`, `$."tags"[0]`, `{"k":null}`}, args)
+
+	sql, _, err = b.Dialect(DialectSQLite).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE docs SET doc = json_patch(json_remove(json_set(doc, ?, json(?)), ?), json(?))", sql)
+}
+
+func TestUpdateJSONPathNoCoalesce(t *testing.T) {
+	t.Parallel()
+	// the document is not created for NULL column and missing parents, see SetJSONPath
+	b := Update("docs").SetJSONPath("doc", []string{"a", "b"}, 1).Where("id = ?", 1)
+
+	sql, _, err := b.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE docs SET doc = jsonb_set(doc, CAST(? AS text[]), CAST(? AS jsonb), true) WHERE id = ?", sql)
+
+	sql, _, err = b.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE docs SET doc = JSON_SET(doc, ?, CAST(? AS JSON)) WHERE id = ?", sql)
+}
+
+func TestUpdateJSONPathErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Update("docs").SetJSONPath("doc", []string{"a"}, 1).Where("id = ?", 1).Dialect(DialectOracle).ToSql()
+	require.EqualError(t, err, "JSON mutation is not supported by the oracle dialect")
+
+	_, _, err = Update("docs").SetJSONPath("doc", []string{"a"}, make(chan int)).ToSql()
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// LIMIT emulation for UPDATE and DELETE statements of PostgreSQL, which has no
+// UPDATE/DELETE ... ORDER BY ... LIMIT, e.g.
+// DELETE FROM t WHERE ctid IN (SELECT ctid FROM t WHERE a = ? ORDER BY id LIMIT 100)
+
+// defaultLimitKey is the row identifier used when no key columns are set.
+const defaultLimitKey = "ctid"
+
+// limitedRows is the subquery condition selecting the limited set of rows.
+type limitedRows struct {
+	keyColumns      []string
+	table           string
+	whereParts      []Sqlizer
+	orderBys        []string
+	limit           *uint64
+	offset          *uint64
+	bindLimitOffset bool
+}
+
+func (l limitedRows) ToSql() (sqlStr string, args []any, err error) {
+	keys := l.keyColumns
+	if len(keys) == 0 {
+		keys = []string{defaultLimitKey}
+	}
+	keyList := strings.Join(keys, ", ")
+
+	sql := &bytes.Buffer{}
+	if len(keys) == 1 {
+		_, _ = sql.WriteString(keyList)
+	} else {
+		_, _ = fmt.Fprintf(sql, "(%s)", keyList)
+	}
+	_, _ = fmt.Fprintf(sql, " IN (SELECT %s FROM %s", keyList, l.table)
+
+	if len(l.whereParts) > 0 {
+		_, _ = sql.WriteString(" WHERE ")
+		if args, err = appendToSql(l.whereParts, sql, " AND ", args); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if len(l.orderBys) > 0 {
+		_, _ = sql.WriteString(" ORDER BY ")
+		_, _ = sql.WriteString(strings.Join(l.orderBys, ", "))
+	}
+
+	if l.limit != nil {
+		args = writeLimitValue(sql, "LIMIT", *l.limit, l.bindLimitOffset, args)
+	}
+
+	if l.offset != nil {
+		args = writeLimitValue(sql, "OFFSET", *l.offset, l.bindLimitOffset, args)
+	}
+
+	_, _ = sql.WriteString(")")
+	return sql.String(), args, nil
+}
+
+// needsLimitEmulation returns true if ORDER BY, LIMIT or OFFSET must be moved to the subquery.
+func needsLimitEmulation(dialect Dialect, orderBys []string, limit, offset *uint64) bool {
+	return dialect == DialectPostgres && (len(orderBys) > 0 || limit != nil || offset != nil)
+}
+
+// emulateLimit returns a copy of the update data with ORDER BY, LIMIT and OFFSET
+// moved to the subquery condition.
+func (d *updateData) emulateLimit() (*updateData, error) {
+	if d.From != nil || len(d.Joins) > 0 {
+		return nil, errors.New("update with LIMIT cannot be used with From clause or joins for the postgres dialect")
+	}
+
+	res := *d
+	res.WhereParts = []Sqlizer{limitedRows{
+		keyColumns:      d.LimitKey,
+		table:           d.Table,
+		whereParts:      d.WhereParts,
+		orderBys:        d.OrderBys,
+		limit:           d.Limit,
+		offset:          d.Offset,
+		bindLimitOffset: d.BindLimitOffset,
+	}}
+	res.OrderBys = nil
+	res.Limit = nil
+	res.Offset = nil
+	return &res, nil
+}
+
+// emulateLimit returns a copy of the delete data with ORDER BY, LIMIT and OFFSET
+// moved to the subquery condition.
+func (d *deleteData) emulateLimit() (*deleteData, error) {
+	if len(d.Using) > 0 || len(d.Joins) > 0 {
+		return nil, errors.New("delete with LIMIT cannot be used with Using clause or joins for the postgres dialect")
+	}
+
+	res := *d
+	res.WhereParts = []Sqlizer{limitedRows{
+		keyColumns:      d.LimitKey,
+		table:           d.From,
+		whereParts:      d.WhereParts,
+		orderBys:        d.OrderBys,
+		limit:           d.Limit,
+		offset:          d.Offset,
+		bindLimitOffset: d.BindLimitOffset,
+	}}
+	res.OrderBys = nil
+	res.Limit = nil
+	res.Offset = nil
+	return &res, nil
+}
+
+// LimitKey sets the columns identifying the rows for the LIMIT emulation.
+//
+// PostgreSQL does not support ORDER BY, LIMIT and OFFSET in UPDATE statements, so for
+// the postgres dialect they are moved to the subquery:
+//
+//	UPDATE t SET a = ? WHERE ctid IN (SELECT ctid FROM t WHERE b = ? ORDER BY id LIMIT 10)
+//
+// The physical row identifier ctid is used by default. Set the primary key columns
+// for tables without ctid (e.g. partitioned or foreign tables) or PostgreSQL compatible
+// databases: "WHERE (a, b) IN (SELECT a, b FROM t ...)".
+func (b UpdateBuilder) LimitKey(columns ...string) UpdateBuilder {
+	return builder.Set(b, "LimitKey", slices.Clone(columns)).(UpdateBuilder)
+}
+
+// LimitKey sets the columns identifying the rows for the LIMIT emulation.
+//
+// PostgreSQL does not support ORDER BY, LIMIT and OFFSET in DELETE statements, so for
+// the postgres dialect they are moved to the subquery:
+//
+//	DELETE FROM t WHERE ctid IN (SELECT ctid FROM t WHERE b = ? ORDER BY id LIMIT 10)
+//
+// See UpdateBuilder.LimitKey for more information.
+func (b DeleteBuilder) LimitKey(columns ...string) DeleteBuilder {
+	return builder.Set(b, "LimitKey", slices.Clone(columns)).(DeleteBuilder)
+}
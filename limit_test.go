@@ -0,0 +1,89 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteLimitPostgres(t *testing.T) {
+	t.Parallel()
+	b := Delete("a").
+		Where("b = ?", 1).
+		OrderBy("c").
+		Limit(2).
+		Offset(3).
+		Returning("id").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "DELETE FROM a WHERE ctid IN (SELECT ctid FROM a WHERE b = $1 ORDER BY c LIMIT 2 OFFSET 3) " +
+		"RETURNING id"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1}, args)
+}
+
+func TestDeleteLimitKey(t *testing.T) {
+	t.Parallel()
+	b := Delete("a").
+		Where(Eq{"b": 1}).
+		Where("c > ?", 2).
+		Limit(10).
+		BindLimitOffset(true).
+		LimitKey("id", "region").
+		Dialect(DialectPostgres)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "DELETE FROM a WHERE (id, region) IN " +
+		"(SELECT id, region FROM a WHERE b = ? AND c > ? LIMIT ?)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, 2, uint64(10)}, args)
+}
+
+func TestDeleteLimitMySQL(t *testing.T) {
+	t.Parallel()
+	sql, _, err := Delete("a").Where("b = ?", 1).OrderBy("c").Limit(2).LimitKey("id").ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE b = ? ORDER BY c LIMIT 2", sql)
+}
+
+func TestDeleteLimitPostgresUsingErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Delete("a").Using("b").Where("a.id = b.id").Limit(2).PlaceholderFormat(Dollar).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpdateLimitPostgres(t *testing.T) {
+	t.Parallel()
+	b := Update("a").
+		Set("b", 1).
+		Where("c = ?", 2).
+		OrderBy("d").
+		Limit(3).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "UPDATE a SET b = $1 WHERE ctid IN (SELECT ctid FROM a WHERE c = $2 ORDER BY d LIMIT 3)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, 2}, args)
+}
+
+func TestUpdateLimitKeyWithoutWhere(t *testing.T) {
+	t.Parallel()
+	sql, _, err := Update("a").Set("b", 1).Limit(3).LimitKey("id").AllowFullTable().PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET b = $1 WHERE id IN (SELECT id FROM a LIMIT 3)", sql)
+}
+
+func TestUpdateLimitPostgresFromErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Update("a").Set("b", 1).From("c").Where("a.id = c.id").Limit(3).PlaceholderFormat(Dollar).ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,503 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// MERGE statement helper
+// e.g.
+// MERGE INTO t USING s ON t.id = s.id
+// WHEN MATCHED AND s.deleted THEN DELETE
+// WHEN MATCHED THEN UPDATE SET name = s.name
+// WHEN NOT MATCHED THEN INSERT (id,name) VALUES (s.id,s.name)
+//
+// Supported by PostgreSQL 15+, SQL Server and Oracle.
+
+type mergeAction int
+
+const (
+	mergeUpdate mergeAction = iota
+	mergeDelete
+	mergeDoNothing
+	mergeInsert
+)
+
+// mergeWhen is WHEN [NOT] MATCHED branch of MERGE statement.
+type mergeWhen struct {
+	matched bool
+	cond    Sqlizer
+	action  mergeAction
+	clauses []setClause // SET clauses of UPDATE or columns and values of INSERT
+}
+
+type mergeData struct {
+	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
+	Prefixes          []Sqlizer
+	Into              string
+	Using             Sqlizer
+	UsingAlias        string // alias of UsingSelect subquery
+	OnParts           []Sqlizer
+	Whens             []mergeWhen
+	Returning         []any
+	Suffixes          []Sqlizer
+}
+
+// mapClauses returns the clauses sorted by the column name.
+func mapClauses(clauses map[string]any) []setClause {
+	res := make([]setClause, 0, len(clauses))
+	for column, value := range clauses {
+		res = append(res, setClause{column: column, value: value})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].column < res[j].column })
+	return res
+}
+
+// mergeValueSQL builds INSERT value of MERGE statement.
+func mergeValueSQL(value any, dialect Dialect) (sql string, args []any, err error) {
+	if ds, ok := value.(dialectSqlizer); ok {
+		return ds.toSqlDialect(dialect)
+	}
+
+	vs, ok := value.(Sqlizer)
+	if !ok {
+		return "?", []any{value}, nil
+	}
+
+	if sql, args, err = nestedToSql(vs); err != nil {
+		return "", nil, err
+	}
+	if _, isSelect := vs.(SelectBuilder); isSelect {
+		sql = fmt.Sprintf("(%s)", sql)
+	}
+	return sql, args, nil
+}
+
+func (d *mergeData) writeUsing(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	_, _ = sql.WriteString(" USING ")
+
+	if _, ok := d.Using.(valuesTable); ok && dialect == DialectOracle {
+		return nil, errUnsupported("MERGE with VALUES source", dialect)
+	}
+
+	usingSql, usingArgs, err := nestedToSql(d.Using)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case d.UsingAlias == "":
+		_, _ = sql.WriteString(usingSql)
+	case dialect == DialectOracle:
+		// Oracle does not support AS keyword for table aliases
+		_, _ = fmt.Fprintf(sql, "(%s) %s", usingSql, d.UsingAlias)
+	default:
+		_, _ = fmt.Fprintf(sql, "(%s) AS %s", usingSql, d.UsingAlias)
+	}
+	return append(args, usingArgs...), nil
+}
+
+func (d *mergeData) writeOn(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	_, _ = sql.WriteString(" ON ")
+	if dialect == DialectOracle {
+		_, _ = sql.WriteString("(")
+	}
+
+	args, err := appendToSql(d.OnParts, sql, " AND ", args)
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect == DialectOracle {
+		_, _ = sql.WriteString(")")
+	}
+	return args, nil
+}
+
+func writeMergeUpdate(sql *bytes.Buffer, clauses []setClause, dialect Dialect, args []any) ([]any, error) {
+	_, _ = sql.WriteString("UPDATE SET ")
+
+	setSqls := make([]string, len(clauses))
+	for i, sc := range clauses {
+		setSql, setArgs, err := buildSetClauseSQL(sc, dialect)
+		if err != nil {
+			return nil, err
+		}
+		setSqls[i] = setSql
+		args = append(args, setArgs...)
+	}
+
+	_, _ = sql.WriteString(strings.Join(setSqls, ", "))
+	return args, nil
+}
+
+func writeMergeInsert(sql *bytes.Buffer, clauses []setClause, dialect Dialect, args []any) ([]any, error) {
+	columns := make([]string, len(clauses))
+	values := make([]string, len(clauses))
+	for i, sc := range clauses {
+		valSql, valArgs, err := mergeValueSQL(sc.value, dialect)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = sc.column
+		values[i] = valSql
+		args = append(args, valArgs...)
+	}
+
+	_, _ = fmt.Fprintf(sql, "INSERT (%s) VALUES (%s)", strings.Join(columns, ","), strings.Join(values, ","))
+	return args, nil
+}
+
+// writeCondition writes " <keyword> cond" if the condition is set.
+func writeCondition(sql *bytes.Buffer, keyword string, cond Sqlizer, args []any) ([]any, error) {
+	if cond == nil {
+		return args, nil
+	}
+
+	_, _ = fmt.Fprintf(sql, " %s ", keyword)
+	return appendToSql([]Sqlizer{cond}, sql, "", args)
+}
+
+// writeWhens writes WHEN branches in the standard syntax of PostgreSQL and SQL Server.
+func (d *mergeData) writeWhens(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	var err error
+	for _, w := range d.Whens {
+		if w.matched {
+			_, _ = sql.WriteString(" WHEN MATCHED")
+		} else {
+			_, _ = sql.WriteString(" WHEN NOT MATCHED")
+		}
+
+		if args, err = writeCondition(sql, "AND", w.cond, args); err != nil {
+			return nil, err
+		}
+		_, _ = sql.WriteString(" THEN ")
+
+		switch w.action {
+		case mergeUpdate:
+			args, err = writeMergeUpdate(sql, w.clauses, dialect, args)
+		case mergeInsert:
+			args, err = writeMergeInsert(sql, w.clauses, dialect, args)
+		case mergeDelete:
+			_, _ = sql.WriteString("DELETE")
+		case mergeDoNothing:
+			if dialect == DialectSQLServer {
+				return nil, errUnsupported("MERGE ... DO NOTHING", dialect)
+			}
+			_, _ = sql.WriteString("DO NOTHING")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// writeOracleWhens writes WHEN branches in Oracle syntax, where branch conditions are
+// WHERE clauses of the actions and DELETE is a part of UPDATE branch:
+// WHEN MATCHED THEN UPDATE SET ... WHERE ... DELETE WHERE ... WHEN NOT MATCHED THEN INSERT ... WHERE ...
+func (d *mergeData) writeOracleWhens(sql *bytes.Buffer, args []any) ([]any, error) {
+	var update, del, insert *mergeWhen
+	for i := range d.Whens {
+		w := &d.Whens[i]
+		var slot **mergeWhen
+		switch w.action {
+		case mergeUpdate:
+			slot = &update
+		case mergeDelete:
+			slot = &del
+		case mergeInsert:
+			slot = &insert
+		case mergeDoNothing:
+			return nil, errUnsupported("MERGE ... DO NOTHING", DialectOracle)
+		}
+		if *slot != nil {
+			return nil, errors.New("oracle MERGE supports one UPDATE, DELETE and INSERT branch")
+		}
+		*slot = w
+	}
+
+	if del != nil && (update == nil || del.cond == nil) {
+		return nil, errors.New("oracle MERGE DELETE requires UPDATE branch and condition")
+	}
+
+	var err error
+	if update != nil {
+		_, _ = sql.WriteString(" WHEN MATCHED THEN ")
+		if args, err = writeMergeUpdate(sql, update.clauses, DialectOracle, args); err != nil {
+			return nil, err
+		}
+		if args, err = writeCondition(sql, "WHERE", update.cond, args); err != nil {
+			return nil, err
+		}
+		if del != nil {
+			_, _ = sql.WriteString(" DELETE")
+			if args, err = writeCondition(sql, "WHERE", del.cond, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if insert != nil {
+		_, _ = sql.WriteString(" WHEN NOT MATCHED THEN ")
+		if args, err = writeMergeInsert(sql, insert.clauses, DialectOracle, args); err != nil {
+			return nil, err
+		}
+		if args, err = writeCondition(sql, "WHERE", insert.cond, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// checkWhens returns an error if UPDATE or INSERT branch has no columns.
+func (d *mergeData) checkWhens() error {
+	for _, w := range d.Whens {
+		if len(w.clauses) > 0 {
+			continue
+		}
+		switch w.action {
+		case mergeUpdate:
+			return errors.New("merge WHEN MATCHED UPDATE branch must have at least one column")
+		case mergeInsert:
+			return errors.New("merge WHEN NOT MATCHED INSERT branch must have at least one column")
+		case mergeDelete, mergeDoNothing:
+		}
+	}
+	return nil
+}
+
+func (d *mergeData) toSqlRaw() (sqlStr string, args []any, err error) {
+	switch {
+	case d.Into == "":
+		return "", nil, errors.New("merge statements must specify a table")
+	case d.Using == nil:
+		return "", nil, errors.New("merge statements must specify a source")
+	case len(d.OnParts) == 0:
+		return "", nil, errors.New("merge statements must specify a join condition")
+	case len(d.Whens) == 0:
+		return "", nil, errors.New("merge statements must have at least one WHEN clause")
+	}
+	if err = d.checkWhens(); err != nil {
+		return "", nil, err
+	}
+
+	dialect := resolveDialect(d.Dialect, d.PlaceholderFormat)
+	switch dialect { //nolint:exhaustive // other dialects support MERGE
+	case DialectMySQL, DialectSQLite:
+		return "", nil, errUnsupported("MERGE", dialect)
+	}
+
+	if err = checkReturning(dialect, d.Returning); err != nil {
+		return "", nil, err
+	}
+
+	sql := &bytes.Buffer{}
+
+	if len(d.Prefixes) > 0 {
+		if args, err = appendToSql(d.Prefixes, sql, " ", args); err != nil {
+			return "", nil, err
+		}
+		_, _ = sql.WriteString(" ")
+	}
+
+	_, _ = sql.WriteString("MERGE INTO ")
+	_, _ = sql.WriteString(d.Into)
+
+	if args, err = d.writeUsing(sql, dialect, args); err != nil {
+		return "", nil, err
+	}
+
+	if args, err = d.writeOn(sql, dialect, args); err != nil {
+		return "", nil, err
+	}
+
+	if dialect == DialectOracle {
+		args, err = d.writeOracleWhens(sql, args)
+	} else {
+		args, err = d.writeWhens(sql, dialect, args)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if args, err = writeReturning(sql, d.Returning, dialect, outputInserted, args); err != nil {
+		return "", nil, err
+	}
+
+	if len(d.Suffixes) > 0 {
+		_, _ = sql.WriteString(" ")
+		if args, err = appendToSql(d.Suffixes, sql, " ", args); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if dialect == DialectSQLServer {
+		// SQL Server requires MERGE statement to be terminated by a semicolon
+		_, _ = sql.WriteString(";")
+	}
+
+	return sql.String(), args, nil
+}
+
+func (d *mergeData) ToSql() (sqlStr string, args []any, err error) {
+	s, a, e := d.toSqlRaw()
+	if e != nil {
+		return "", nil, e
+	}
+	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(s)
+	return sqlStr, a, err
+}
+
+// Builder
+
+// MergeBuilder builds SQL MERGE statements.
+type MergeBuilder builder.Builder
+
+func init() { //nolint:gochecknoinits // required to register MergeBuilder
+	builder.Register(MergeBuilder{}, mergeData{}) //nolint:exhaustruct // empty struct is fine
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b MergeBuilder) PlaceholderFormat(f PlaceholderFormat) MergeBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(MergeBuilder)
+}
+
+// Dialect sets the SQL dialect of the query. If not set, the dialect is
+// detected by the PlaceholderFormat.
+func (b MergeBuilder) Dialect(d Dialect) MergeBuilder {
+	return builder.Set(b, "Dialect", d).(MergeBuilder)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b MergeBuilder) ToSql() (sql string, args []any, err error) {
+	data := builder.GetStruct(b).(mergeData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b MergeBuilder) MustSql() (sql string, args []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// toSqlRaw builds SQL with raw placeholders ("?") without applying PlaceholderFormat.
+func (b MergeBuilder) toSqlRaw() (sql string, args []any, err error) {
+	data := builder.GetStruct(b).(mergeData)
+	return data.toSqlRaw()
+}
+
+// Prefix adds an expression to the beginning of the query.
+func (b MergeBuilder) Prefix(sql string, args ...any) MergeBuilder {
+	return b.PrefixExpr(Expr(sql, args...))
+}
+
+// PrefixExpr adds an expression to the very beginning of the query.
+func (b MergeBuilder) PrefixExpr(e Sqlizer) MergeBuilder {
+	return builder.Append(b, "Prefixes", e).(MergeBuilder)
+}
+
+// Into sets the target table of the query.
+func (b MergeBuilder) Into(table string) MergeBuilder {
+	return builder.Set(b, "Into", table).(MergeBuilder)
+}
+
+// Using sets the source table of the query, e.g. "src s".
+func (b MergeBuilder) Using(table string) MergeBuilder {
+	b = builder.Set(b, "UsingAlias", "").(MergeBuilder)
+	return builder.Set(b, "Using", newPart(table)).(MergeBuilder)
+}
+
+// UsingSelect sets a subquery as the source of the query.
+func (b MergeBuilder) UsingSelect(from SelectBuilder, alias string) MergeBuilder {
+	b = builder.Set(b, "UsingAlias", alias).(MergeBuilder)
+	return builder.Set(b, "Using", from).(MergeBuilder)
+}
+
+// UsingValues sets a VALUES list as the source of the query.
+// The list must have an alias, see ValuesBuilder.As. Oracle does not support VALUES lists.
+func (b MergeBuilder) UsingValues(from ValuesBuilder) MergeBuilder {
+	b = builder.Set(b, "UsingAlias", "").(MergeBuilder)
+	return builder.Set(b, "Using", valuesTable{values: from}).(MergeBuilder)
+}
+
+// On adds the join condition of the target and the source. Several conditions are joined with AND.
+//
+// See SelectBuilder.Where for the supported condition types.
+func (b MergeBuilder) On(pred any, args ...any) MergeBuilder {
+	return builder.Append(b, "OnParts", newWherePart(pred, args...)).(MergeBuilder)
+}
+
+func (b MergeBuilder) when(w mergeWhen) MergeBuilder {
+	return builder.Append(b, "Whens", w).(MergeBuilder)
+}
+
+// WhenMatchedUpdate adds "WHEN MATCHED [AND cond] THEN UPDATE SET ..." branch to the query.
+// The condition can be nil. Values can be Sqlizers, e.g. Expr("s.name").
+//
+// Oracle supports one UPDATE branch, and the condition is rendered as WHERE clause of the UPDATE.
+func (b MergeBuilder) WhenMatchedUpdate(cond Sqlizer, clauses map[string]any) MergeBuilder {
+	return b.when(mergeWhen{matched: true, cond: cond, action: mergeUpdate, clauses: mapClauses(clauses)})
+}
+
+// WhenMatchedDelete adds "WHEN MATCHED [AND cond] THEN DELETE" branch to the query.
+// The condition can be nil.
+//
+// For Oracle it is rendered as "DELETE WHERE cond" clause of the UPDATE branch,
+// so the UPDATE branch and the condition are required.
+func (b MergeBuilder) WhenMatchedDelete(cond Sqlizer) MergeBuilder {
+	return b.when(mergeWhen{matched: true, cond: cond, action: mergeDelete, clauses: nil})
+}
+
+// WhenMatchedDoNothing adds "WHEN MATCHED [AND cond] THEN DO NOTHING" branch to the query.
+// The condition can be nil. PostgreSQL only.
+func (b MergeBuilder) WhenMatchedDoNothing(cond Sqlizer) MergeBuilder {
+	return b.when(mergeWhen{matched: true, cond: cond, action: mergeDoNothing, clauses: nil})
+}
+
+// WhenNotMatchedInsert adds "WHEN NOT MATCHED [AND cond] THEN INSERT (...) VALUES (...)" branch to the query.
+// The condition can be nil. Values can be Sqlizers, e.g. Expr("s.name").
+//
+// Oracle supports one INSERT branch, and the condition is rendered as WHERE clause of the INSERT.
+func (b MergeBuilder) WhenNotMatchedInsert(cond Sqlizer, clauses map[string]any) MergeBuilder {
+	return b.when(mergeWhen{matched: false, cond: cond, action: mergeInsert, clauses: mapClauses(clauses)})
+}
+
+// WhenNotMatchedDoNothing adds "WHEN NOT MATCHED [AND cond] THEN DO NOTHING" branch to the query.
+// The condition can be nil. PostgreSQL only.
+func (b MergeBuilder) WhenNotMatchedDoNothing(cond Sqlizer) MergeBuilder {
+	return b.when(mergeWhen{matched: false, cond: cond, action: mergeDoNothing, clauses: nil})
+}
+
+// Returning adds RETURNING clause columns to the query (PostgreSQL 17+).
+// For SQL Server OUTPUT clause is rendered, and plain column names are qualified with "inserted".
+// RETURNING is not supported by Oracle dialect.
+func (b MergeBuilder) Returning(columns ...any) MergeBuilder {
+	return builder.Extend(b, "Returning", columns).(MergeBuilder)
+}
+
+// Suffix adds an expression to the end of the query.
+func (b MergeBuilder) Suffix(sql string, args ...any) MergeBuilder {
+	return b.SuffixExpr(Expr(sql, args...))
+}
+
+// SuffixExpr adds an expression to the end of the query.
+func (b MergeBuilder) SuffixExpr(e Sqlizer) MergeBuilder {
+	return builder.Append(b, "Suffixes", e).(MergeBuilder)
+}
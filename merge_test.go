@@ -0,0 +1,151 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeBuilderToSql(t *testing.T) {
+	t.Parallel()
+	b := Merge("customers c").
+		Using("new_customers s").
+		On("c.id = s.id").
+		WhenMatchedDelete(Expr("s.deleted = ?", true)).
+		WhenMatchedUpdate(Expr("c.name <> s.name"), map[string]any{"name": Expr("s.name"), "updated_at": Now()}).
+		WhenMatchedDoNothing(nil).
+		WhenNotMatchedInsert(nil, map[string]any{"id": Expr("s.id"), "name": Expr("s.name"), "source": "import"}).
+		Returning("c.id").
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "MERGE INTO customers c USING new_customers s ON c.id = s.id " +
+		"WHEN MATCHED AND s.deleted = $1 THEN DELETE " +
+		"WHEN MATCHED AND c.name <> s.name THEN UPDATE SET name = s.name, updated_at = now() " +
+		"WHEN MATCHED THEN DO NOTHING " +
+		"WHEN NOT MATCHED THEN INSERT (id,name,source) VALUES (s.id,s.name,$2) " +
+		"RETURNING c.id"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{true, "import"}, args)
+}
+
+func TestMergeBuilderUsingSelect(t *testing.T) {
+	t.Parallel()
+	b := StatementBuilder.PlaceholderFormat(AtP).
+		Merge("stock").
+		UsingSelect(Select("item_id", "SUM(qty) AS qty").From("sales").Where("day = ?", 3).GroupBy("item_id"), "s").
+		On("stock.item_id = s.item_id").
+		WhenMatchedUpdate(nil, map[string]any{"qty": Expr("stock.qty - s.qty")}).
+		WhenNotMatchedInsert(Expr("s.qty > ?", 0), map[string]any{"item_id": Expr("s.item_id"), "qty": Expr("-s.qty")}).
+		Returning("item_id")
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "MERGE INTO stock " +
+		"USING (SELECT item_id, SUM(qty) AS qty FROM sales WHERE day = @p1 GROUP BY item_id) AS s " +
+		"ON stock.item_id = s.item_id " +
+		"WHEN MATCHED THEN UPDATE SET qty = stock.qty - s.qty " +
+		"WHEN NOT MATCHED AND s.qty > @p2 THEN INSERT (item_id,qty) VALUES (s.item_id,-s.qty) " +
+		"OUTPUT inserted.item_id;"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{3, 0}, args)
+}
+
+func TestMergeBuilderUsingValues(t *testing.T) {
+	t.Parallel()
+	b := Merge("items t").
+		UsingValues(Values([][]any{{1, "a"}, {2, "b"}}).As("v", "id", "name")).
+		On("t.id = v.id").
+		WhenMatchedUpdate(nil, map[string]any{"name": Expr("v.name")}).
+		WhenNotMatchedInsert(nil, map[string]any{"id": Expr("v.id"), "name": Expr("v.name")}).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "MERGE INTO items t USING (VALUES ($1, $2), ($3, $4)) AS v(id, name) ON t.id = v.id " +
+		"WHEN MATCHED THEN UPDATE SET name = v.name " +
+		"WHEN NOT MATCHED THEN INSERT (id,name) VALUES (v.id,v.name)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, "a", 2, "b"}, args)
+}
+
+func TestMergeBuilderOracle(t *testing.T) {
+	t.Parallel()
+	b := Merge("customers c").
+		UsingSelect(Select("id", "name", "deleted").From("new_customers"), "s").
+		On("c.id = s.id").
+		WhenMatchedUpdate(Expr("c.name <> s.name"), map[string]any{"name": Expr("s.name")}).
+		WhenMatchedDelete(Expr("s.deleted = ?", 1)).
+		WhenNotMatchedInsert(Expr("s.deleted = ?", 0), map[string]any{"id": Expr("s.id"), "name": Expr("s.name")}).
+		PlaceholderFormat(Colon)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "MERGE INTO customers c USING (SELECT id, name, deleted FROM new_customers) s ON (c.id = s.id) " +
+		"WHEN MATCHED THEN UPDATE SET name = s.name WHERE c.name <> s.name DELETE WHERE s.deleted = :1 " +
+		"WHEN NOT MATCHED THEN INSERT (id,name) VALUES (s.id,s.name) WHERE s.deleted = :2"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, 0}, args)
+}
+
+func TestMergeBuilderCte(t *testing.T) {
+	t.Parallel()
+	b := With("src").As(Select("id", "name").From("staging").Where("batch = ?", 7)).
+		Merge(Merge("items t").
+			Using("src s").
+			On("t.id = s.id").
+			WhenMatchedUpdate(nil, map[string]any{"name": Expr("s.name"), "batch": 7})).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH src AS (SELECT id, name FROM staging WHERE batch = $1) " +
+		"MERGE INTO items t USING src s ON t.id = s.id WHEN MATCHED THEN UPDATE SET batch = $2, name = s.name"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{7, 7}, args)
+}
+
+func TestMergeBuilderErr(t *testing.T) {
+	t.Parallel()
+	valid := Merge("t").Using("s").On("t.id = s.id").WhenMatchedDelete(nil)
+
+	_, _, err := Merge("t").On("t.id = s.id").WhenMatchedDelete(nil).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Merge("t").Using("s").WhenMatchedDelete(nil).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Merge("t").Using("s").On("t.id = s.id").ToSql()
+	require.Error(t, err)
+
+	_, _, err = valid.Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "MERGE is not supported by the mysql dialect")
+
+	_, _, err = valid.WhenNotMatchedDoNothing(nil).Dialect(DialectSQLServer).ToSql()
+	require.EqualError(t, err, "MERGE ... DO NOTHING is not supported by the sqlserver dialect")
+
+	_, _, err = valid.Dialect(DialectOracle).ToSql()
+	require.Error(t, err)
+
+	_, _, err = valid.Returning("id").Dialect(DialectOracle).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Merge("t").UsingValues(Values([][]any{{1}}).As("v", "id")).On("t.id = v.id").
+		WhenMatchedDelete(nil).Dialect(DialectOracle).ToSql()
+	require.EqualError(t, err, "MERGE with VALUES source is not supported by the oracle dialect")
+
+	_, _, err = Merge("t").Using("s").On("t.id = s.id").WhenMatchedUpdate(nil, map[string]any{}).ToSql()
+	require.EqualError(t, err, "merge WHEN MATCHED UPDATE branch must have at least one column")
+
+	_, _, err = valid.WhenNotMatchedInsert(nil, nil).ToSql()
+	require.EqualError(t, err, "merge WHEN NOT MATCHED INSERT branch must have at least one column")
+
+	assert.Panics(t, func() { Merge("t").MustSql() })
+}
@@ -0,0 +1,170 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column mutation helpers for SET clauses of UPDATE statements.
+
+// nowExpr is the current timestamp of the dialect.
+type nowExpr struct{}
+
+// Now returns the current timestamp expression. It renders as now() for PostgreSQL,
+// NOW() for MySQL, SYSDATETIMEOFFSET() for SQL Server, SYSTIMESTAMP for Oracle
+// and CURRENT_TIMESTAMP otherwise.
+func Now() Sqlizer {
+	return nowExpr{}
+}
+
+// ToSql builds the standard CURRENT_TIMESTAMP expression.
+func (e nowExpr) ToSql() (sql string, args []any, err error) {
+	return e.toSqlDialect(DialectUndefined)
+}
+
+func (e nowExpr) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	switch dialect {
+	case DialectPostgres:
+		return "now()", nil, nil
+	case DialectMySQL:
+		return "NOW()", nil, nil
+	case DialectSQLServer:
+		return "SYSDATETIMEOFFSET()", nil, nil
+	case DialectOracle:
+		return "SYSTIMESTAMP", nil, nil
+	case DialectSQLite, DialectUndefined:
+	}
+	return "CURRENT_TIMESTAMP", nil, nil
+}
+
+// columnMutation is a SET clause value, which can be combined with the next mutation
+// of the same column, because a column cannot be assigned twice in one UPDATE.
+type columnMutation interface {
+	Sqlizer
+	combine(next columnMutation) (columnMutation, bool)
+}
+
+// mergeSetClauses combines mutations of the same column into one assignment
+// placed at the position of the first mutation. A column can be assigned only once,
+// so the mutations which cannot be combined (e.g. Set and ArrayAppend, or two Increments)
+// of the same column are an error.
+func mergeSetClauses(clauses []setClause) ([]setClause, error) {
+	res := make([]setClause, 0, len(clauses))
+	positions := make(map[string]int)
+	for _, sc := range clauses {
+		pos, ok := positions[sc.column]
+		if !ok {
+			positions[sc.column] = len(res)
+			res = append(res, sc)
+			continue
+		}
+
+		prev, prevOk := res[pos].value.(columnMutation)
+		next, nextOk := sc.value.(columnMutation)
+		if !prevOk || !nextOk {
+			return nil, fmt.Errorf("column %s is assigned more than once", sc.column)
+		}
+		combined, ok := prev.combine(next)
+		if !ok {
+			return nil, fmt.Errorf("column %s is assigned more than once", sc.column)
+		}
+		res[pos] = setClause{column: sc.column, value: combined}
+	}
+	return res, nil
+}
+
+// arrayOp is a PostgreSQL array function call with the value argument.
+type arrayOp struct {
+	function string
+	value    any
+}
+
+// arrayExpr is a chain of PostgreSQL array functions applied to the column,
+// e.g. array_remove(array_append(column, ?), ?).
+type arrayExpr struct {
+	column string
+	ops    []arrayOp
+}
+
+// ToSql builds the expression in PostgreSQL syntax.
+func (e arrayExpr) ToSql() (sql string, args []any, err error) {
+	return e.toSqlDialect(DialectPostgres)
+}
+
+func (e arrayExpr) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	sql = e.column
+	for _, op := range e.ops {
+		if dialect != DialectPostgres && dialect != DialectUndefined {
+			return "", nil, errUnsupported(op.function, dialect)
+		}
+		sql = fmt.Sprintf("%s(%s, ?)", op.function, sql)
+		args = append(args, op.value)
+	}
+	return sql, args, nil
+}
+
+// combine appends the functions of the next array mutation of the same column.
+func (e arrayExpr) combine(next columnMutation) (columnMutation, bool) {
+	n, ok := next.(arrayExpr)
+	if !ok {
+		return nil, false
+	}
+	return arrayExpr{column: e.column, ops: append(append([]arrayOp{}, e.ops...), n.ops...)}, true
+}
+
+// rowSubquery is a subquery assigned to the list of columns.
+type rowSubquery struct {
+	query SelectBuilder
+}
+
+func (e rowSubquery) ToSql() (sql string, args []any, err error) {
+	return e.toSqlDialect(DialectUndefined)
+}
+
+func (e rowSubquery) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	if dialect == DialectMySQL || dialect == DialectSQLServer {
+		return "", nil, errUnsupported("row assignment", dialect)
+	}
+
+	sql, args, err = nestedToSql(e.query)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("(%s)", sql), args, nil
+}
+
+// Increment adds "column = column + n" SET clause to the query.
+func (b UpdateBuilder) Increment(column string, n any) UpdateBuilder {
+	return b.Set(column, Expr(column+" + ?", n))
+}
+
+// Decrement adds "column = column - n" SET clause to the query.
+func (b UpdateBuilder) Decrement(column string, n any) UpdateBuilder {
+	return b.Set(column, Expr(column+" - ?", n))
+}
+
+// SetIfNull adds "column = COALESCE((column), value)" SET clause to the query,
+// so the value is set only if the column is NULL.
+func (b UpdateBuilder) SetIfNull(column string, value any) UpdateBuilder {
+	return b.Set(column, Coalesce(value, Expr(column)))
+}
+
+// ArrayAppend adds "column = array_append(column, value)" SET clause to the query (PostgreSQL only).
+// Several array mutations of the same column are nested into one assignment:
+// "column = array_remove(array_append(column, ?), ?)".
+func (b UpdateBuilder) ArrayAppend(column string, value any) UpdateBuilder {
+	return b.Set(column, arrayExpr{column: column, ops: []arrayOp{{function: "array_append", value: value}}})
+}
+
+// ArrayRemove adds "column = array_remove(column, value)" SET clause to the query (PostgreSQL only).
+//
+// See ArrayAppend for more information.
+func (b UpdateBuilder) ArrayRemove(column string, value any) UpdateBuilder {
+	return b.Set(column, arrayExpr{column: column, ops: []arrayOp{{function: "array_remove", value: value}}})
+}
+
+// SetRow adds "(a, b) = (SELECT ...)" SET clause to the query.
+// Row assignment is not supported by MySQL and SQL Server dialects.
+func (b UpdateBuilder) SetRow(columns []string, query SelectBuilder) UpdateBuilder {
+	return b.Set(fmt.Sprintf("(%s)", strings.Join(columns, ", ")), rowSubquery{query: query})
+}
@@ -0,0 +1,120 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMutations(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Update("items").
+		Increment("qty", 2).
+		Decrement("reserved", 1).
+		SetIfNull("note", "n/a").
+		ArrayAppend("tags", "new").
+		ArrayRemove("tags", "old").
+		Set("updated_at", Now()).
+		Where("id = ?", 1).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE items SET qty = qty + $1, reserved = reserved - $2, note = COALESCE((note), $3), "+
+		"tags = array_remove(array_append(tags, $4), $5), updated_at = now() WHERE id = $6", sql)
+	assert.Equal(t, []any{2, 1, "n/a", "new", "old", 1}, args)
+}
+
+func TestUpdateNow(t *testing.T) {
+	t.Parallel()
+	b := Update("items").Set("updated_at", Now()).AllowFullTable()
+
+	for dialect, expected := range map[Dialect]string{
+		DialectUndefined: "CURRENT_TIMESTAMP",
+		DialectPostgres:  "now()",
+		DialectMySQL:     "NOW()",
+		DialectSQLite:    "CURRENT_TIMESTAMP",
+		DialectSQLServer: "SYSDATETIMEOFFSET()",
+		DialectOracle:    "SYSTIMESTAMP",
+	} {
+		sql, _, err := b.Dialect(dialect).ToSql()
+		require.NoError(t, err)
+		assert.Equal(t, "UPDATE items SET updated_at = "+expected, sql)
+	}
+
+	sql, _, err := Now().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "CURRENT_TIMESTAMP", sql)
+
+	sql, _, err = Insert("users").Columns("id", "updated_at").Values(1, Now()).
+		OnConflict("id").DoUpdate().Set("updated_at", Now()).End().
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,updated_at) VALUES ($1,CURRENT_TIMESTAMP) "+
+		"ON CONFLICT (id) DO UPDATE SET updated_at = now()", sql)
+}
+
+func TestUpdateSetRow(t *testing.T) {
+	t.Parallel()
+	b := Update("items").
+		SetRow([]string{"price", "qty"}, Select("price", "qty").From("src").Where("src.id = items.id")).
+		Where("id = ?", 1)
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE items SET (price, qty) = (SELECT price, qty FROM src WHERE src.id = items.id) "+
+		"WHERE id = $1", sql)
+	assert.Equal(t, []any{1}, args)
+
+	_, _, err = b.Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "row assignment is not supported by the mysql dialect")
+}
+
+func TestUpdateArrayErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Update("items").ArrayAppend("tags", "new").Where("id = ?", 1).Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "array_append is not supported by the mysql dialect")
+}
+
+func TestUpdateArraySameColumn(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Update("items").
+		ArrayRemove("tags", "old").
+		Set("qty", 1).
+		ArrayAppend("tags", "new").
+		ArrayAppend("labels", "x").
+		ArrayAppend("tags", "newer").
+		Where("id = ?", 1).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE items SET tags = array_append(array_append(array_remove(tags, ?), ?), ?), qty = ?, "+
+		"labels = array_append(labels, ?) WHERE id = ?", sql)
+	assert.Equal(t, []any{"old", "new", "newer", 1, "x", 1}, args)
+}
+
+func TestUpdateColumnAssignedTwice(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Update("items").
+		ArrayAppend("tags", "new").
+		Set("tags", nil).
+		ArrayRemove("tags", "old").
+		Where("id = ?", 1).
+		ToSql()
+	require.EqualError(t, err, "column tags is assigned more than once")
+
+	_, _, err = Update("items").
+		Increment("b", 1).
+		Increment("b", 2).
+		Where("id = ?", 1).
+		ToSql()
+	require.EqualError(t, err, "column b is assigned more than once")
+
+	_, _, err = Update("items").
+		Set("a", 1).
+		Set("a", 2).
+		Where("id = ?", 1).
+		ToSql()
+	require.EqualError(t, err, "column a is assigned more than once")
+}
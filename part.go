@@ -14,6 +14,15 @@ func newPart(pred any, args ...any) Sqlizer {
 	return &part{pred, args}
 }
 
+// newJoinPart creates a join clause part. Sqlizer args of string clause are
+// expanded in place, e.g. "JOIN ? ON t.id = v.id".
+func newJoinPart(pred any, args ...any) Sqlizer {
+	if str, ok := pred.(string); ok {
+		return newPart(Expr(str, args...))
+	}
+	return newPart(pred, args...)
+}
+
 func (p part) ToSql() (sql string, args []any, err error) {
 	switch pred := p.pred.(type) {
 	case nil:
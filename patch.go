@@ -0,0 +1,134 @@
+package squirrel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lann/builder"
+)
+
+// PatchField describes how a top-level field of JSON Merge Patch (RFC 7396) document
+// is applied to a column by UpdateBuilder.ApplyMergePatch.
+type PatchField struct {
+	// Column is the name of the column. The field name is used if empty.
+	Column string
+	// ReadOnly fields cannot be changed, ToSql returns an error if the patch contains them.
+	ReadOnly bool
+	// JSON column is a JSON document, and nested objects of the patch are merged into it.
+	JSON bool
+	// Convert converts the field value to the column value. If nil, strings, booleans
+	// and numbers are converted to string, bool, int64 or float64.
+	Convert func(value json.RawMessage) (any, error)
+}
+
+// decodePatchValue decodes JSON scalar value.
+func decodePatchValue(value json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, nil
+		}
+		return val.Float64()
+	case map[string]any, []any:
+		return nil, errors.New("object and array values are allowed for JSON fields only")
+	}
+	return v, nil
+}
+
+// isJSONObject returns true if the value is JSON object.
+func isJSONObject(value json.RawMessage) bool {
+	value = bytes.TrimSpace(value)
+	return len(value) > 0 && value[0] == '{'
+}
+
+// isJSONNull returns true if the value is JSON null.
+func isJSONNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// patchColumnValue converts the field value to SET clause value.
+func (f PatchField) patchColumnValue(column string, value json.RawMessage) (any, error) {
+	if isJSONNull(value) {
+		return nil, nil
+	}
+
+	if f.Convert != nil {
+		return f.Convert(value)
+	}
+
+	if f.JSON {
+		if isJSONObject(value) {
+			return jsonMutation{column: column, ops: []jsonOp{{kind: jsonOpMergePatch, path: nil, value: value}}}, nil
+		}
+		return string(value), nil
+	}
+
+	return decodePatchValue(value)
+}
+
+// ApplyMergePatch adds SET clauses from JSON Merge Patch (RFC 7396) document.
+// Top-level fields of the patch are mapped to columns by fieldMap, which works as an allowlist:
+// unknown and read-only fields are an error, returned by ToSql.
+//
+// Explicit null sets the column to NULL. Nested objects of JSON fields are merged into
+// the column document: JSON_MERGE_PATCH is used for MySQL, json_patch for SQLite,
+// and for PostgreSQL the patch is applied as jsonb_set and #- operations level by level.
+// NULL column and missing parent objects are replaced by empty objects.
+func (b UpdateBuilder) ApplyMergePatch(patch []byte, fieldMap map[string]PatchField) UpdateBuilder {
+	res, err := b.applyMergePatch(patch, fieldMap)
+	if err != nil {
+		return builder.Set(b, "Err", err).(UpdateBuilder)
+	}
+	return res
+}
+
+func (b UpdateBuilder) applyMergePatch(patch []byte, fieldMap map[string]PatchField) (UpdateBuilder, error) {
+	if !isJSONObject(patch) {
+		return b, errors.New("merge patch must be a JSON object")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return b, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := fieldMap[name]
+		if !ok {
+			return b, fmt.Errorf("merge patch field %q is unknown", name)
+		}
+		if field.ReadOnly {
+			return b, fmt.Errorf("merge patch field %q is read-only", name)
+		}
+
+		column := field.Column
+		if column == "" {
+			column = name
+		}
+
+		value, err := field.patchColumnValue(column, fields[name])
+		if err != nil {
+			return b, fmt.Errorf("merge patch field %q: %w", name, err)
+		}
+		b = b.Set(column, value)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,131 @@
+package squirrel
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func patchTestFields() map[string]PatchField {
+	return map[string]PatchField{
+		"id":       {ReadOnly: true},
+		"name":     {},
+		"nickName": {Column: "nickname"},
+		"age":      {},
+		"settings": {JSON: true},
+		"birthday": {Convert: func(value json.RawMessage) (any, error) {
+			var s string
+			if err := json.Unmarshal(value, &s); err != nil {
+				return nil, err
+			}
+			return time.Parse(time.DateOnly, s)
+		}},
+	}
+}
+
+func TestUpdateApplyMergePatch(t *testing.T) {
+	t.Parallel()
+	patch := `{"name": "moe", "nickName": null, "age": 30, "birthday": "2000-01-02",
+		"settings": {"theme": "dark", "old": null, "ui": {"size": 2}}}`
+
+	b := Update("users").ApplyMergePatch([]byte(patch), patchTestFields())
+
+	sql, args, err := b.Where("id = ?", 1).PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET age = $1, birthday = $2, name = $3, nickname = $4, "+
+		"settings = jsonb_set(jsonb_set(COALESCE(settings, CAST('{}' AS jsonb)) #- CAST($5 AS text[]), "+
+		"CAST($6 AS text[]), CAST($7 AS jsonb), true), "+
+		"CAST($8 AS text[]), jsonb_set(COALESCE(settings -> CAST($9 AS text), CAST('{}' AS jsonb)), "+
+		"CAST($10 AS text[]), CAST($11 AS jsonb), true), true) WHERE id = $12", sql)
+	assert.Equal(t, []any{
+		int64(30), time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), "moe", nil,
+		`{"old"}`, `{"theme"}`, `"dark"`, `{"ui"}`, "ui", `{"size"}`, `2`, 1,
+	}, args)
+
+	sql, args, err = b.Dialect(DialectMySQL).AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(sql,
+		"settings = JSON_MERGE_PATCH(COALESCE(settings, JSON_OBJECT()), CAST(? AS JSON))"), sql)
+	assert.JSONEq(t, `{"theme": "dark", "old": null, "ui": {"size": 2}}`, args[len(args)-1].(string))
+}
+
+func TestUpdateApplyMergePatchPostgres(t *testing.T) {
+	t.Parallel()
+	fields := patchTestFields()
+
+	// missing parent objects and NULL column are replaced by empty objects
+	b := Update("users").ApplyMergePatch([]byte(`{"settings": {"a": {"b": 1}}}`), fields)
+	sql, args, err := b.Where("id = ?", 1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET settings = jsonb_set(COALESCE(settings, CAST('{}' AS jsonb)), CAST(? AS text[]), "+
+		"jsonb_set(COALESCE(settings -> CAST(? AS text), CAST('{}' AS jsonb)), CAST(? AS text[]), "+
+		"CAST(? AS jsonb), true), true) WHERE id = ?", sql)
+	assert.Equal(t, []any{`{"a"}`, "a", `{"b"}`, `1`, 1}, args)
+
+	// empty object creates the missing key
+	b = Update("users").ApplyMergePatch([]byte(`{"settings": {"a": {}}}`), fields)
+	sql, args, err = b.Where("id = ?", 1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET settings = jsonb_set(COALESCE(settings, CAST('{}' AS jsonb)), CAST(? AS text[]), "+
+		"COALESCE(settings -> CAST(? AS text), CAST('{}' AS jsonb)), true) WHERE id = ?", sql)
+	assert.Equal(t, []any{`{"a"}`, "a", 1}, args)
+
+	// the args of the previous mutations are repeated for nested objects
+	b = Update("users").RemoveJSONPath("settings", []string{"x"}).
+		ApplyMergePatch([]byte(`{"settings": {"a": {"b": 1}}}`), fields)
+	sql, args, err = b.Where("id = ?", 1).PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET settings = jsonb_set(COALESCE(settings #- CAST($1 AS text[]), CAST('{}' AS jsonb)), "+
+		"CAST($2 AS text[]), jsonb_set(COALESCE((settings #- CAST($3 AS text[])) -> CAST($4 AS text), "+
+		"CAST('{}' AS jsonb)), CAST($5 AS text[]), CAST($6 AS jsonb), true), true) WHERE id = $7", sql)
+	assert.Equal(t, []any{`{"x"}`, `{"a"}`, `{"x"}`, "a", `{"b"}`, `1`, 1}, args)
+}
+
+func TestUpdateApplyMergePatchValues(t *testing.T) {
+	t.Parallel()
+	b := Update("users").AllowFullTable().
+		ApplyMergePatch([]byte(`{"age": 1.5, "settings": [1, 2]}`), patchTestFields())
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET age = ?, settings = ?", sql)
+	assert.Equal(t, []any{1.5, "[1, 2]"}, args)
+}
+
+func TestUpdateApplyMergePatchPathDialects(t *testing.T) {
+	t.Parallel()
+	b := Update("users").Where("id = ?", 1).
+		ApplyMergePatch([]byte(`{"settings": {"a": {"b": 1}}}`), patchTestFields())
+
+	// NULL column is replaced by empty object, because the merge functions return NULL for NULL document
+	sql, args, err := b.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET settings = JSON_MERGE_PATCH(COALESCE(settings, JSON_OBJECT()), CAST(? AS JSON)) "+
+		"WHERE id = ?", sql)
+	assert.Equal(t, []any{`{"a": {"b": 1}}`, 1}, args)
+
+	sql, args, err = b.Dialect(DialectSQLite).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET settings = json_patch(COALESCE(settings, '{}'), json(?)) WHERE id = ?", sql)
+	assert.Equal(t, []any{`{"a": {"b": 1}}`, 1}, args)
+}
+
+func TestUpdateApplyMergePatchErr(t *testing.T) {
+	t.Parallel()
+	fields := patchTestFields()
+	toSql := func(patch string) error {
+		_, _, err := Update("users").Where("id = ?", 1).ApplyMergePatch([]byte(patch), fields).Set("a", 1).ToSql()
+		return err
+	}
+
+	require.EqualError(t, toSql(`{"id": 2}`), `merge patch field "id" is read-only`)
+	require.EqualError(t, toSql(`{"password": "x"}`), `merge patch field "password" is unknown`)
+	require.Error(t, toSql(`{"name": {"first": "moe"}}`))
+	require.Error(t, toSql(`{"birthday": "tomorrow"}`))
+	require.Error(t, toSql(`[1]`))
+	require.Error(t, toSql(`{"name": `))
+}
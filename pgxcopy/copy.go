@@ -0,0 +1,53 @@
+// Package pgxcopy copies the rows of squirrel insert queries into PostgreSQL tables
+// using COPY protocol of pgx driver.
+package pgxcopy
+
+import (
+	"context"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+	sq "github.com/n-r-w/squirrel"
+)
+
+// CopyFromer is implemented by *pgx.Conn, pgx.Tx and *pgxpool.Pool.
+type CopyFromer interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// CopyFrom copies the rows of the insert query into the table using PostgreSQL COPY protocol.
+// The table is taken from Into ("schema.table" is supported), columns from Columns
+// and rows from Values (or SetMap, Struct, Structs). All rows are kept in memory by the query,
+// use CopyFromSeq to stream huge imports. Returns the number of copied rows.
+//
+// See squirrel.InsertBuilder.CopyRows for the queries which cannot be copied.
+func CopyFrom(ctx context.Context, conn CopyFromer, b sq.InsertBuilder) (int64, error) {
+	table, columns, rows, err := b.CopyRows()
+	if err != nil {
+		return 0, err
+	}
+
+	return conn.CopyFrom(ctx, pgx.Identifier(table), columns, pgx.CopyFromRows(rows))
+}
+
+// CopyFromSeq works like CopyFrom, but takes the rows from the sequence and streams them
+// to the connection one by one (pgx.CopyFromFunc), so that millions of rows are copied
+// without keeping them in memory. The query is used as a template, its own Values are ignored.
+// An invalid row aborts the copy.
+func CopyFromSeq(ctx context.Context, conn CopyFromer, b sq.InsertBuilder, rows iter.Seq[[]any]) (int64, error) {
+	table, columns, seq, err := b.CopyRowsSeq(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	next, stop := iter.Pull2(seq)
+	defer stop()
+
+	return conn.CopyFrom(ctx, pgx.Identifier(table), columns, pgx.CopyFromFunc(func() ([]any, error) {
+		row, err, ok := next()
+		if !ok {
+			return nil, nil // end of data
+		}
+		return row, err
+	}))
+}
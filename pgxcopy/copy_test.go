@@ -0,0 +1,97 @@
+package pgxcopy
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	sq "github.com/n-r-w/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type copyFromerMock struct {
+	table   pgx.Identifier
+	columns []string
+	rows    [][]any
+}
+
+func (m *copyFromerMock) CopyFrom(
+	_ context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource,
+) (int64, error) {
+	m.table = tableName
+	m.columns = columnNames
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		m.rows = append(m.rows, values)
+	}
+	return int64(len(m.rows)), rowSrc.Err()
+}
+
+func TestCopyFrom(t *testing.T) {
+	t.Parallel()
+	conn := &copyFromerMock{} //nolint:exhaustruct // fields are filled by CopyFrom
+
+	n, err := CopyFrom(context.Background(), conn,
+		sq.Insert("public.users").Columns("id", "name").Values(1, "moe").Values(2, "larry"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, pgx.Identifier{"public", "users"}, conn.table)
+	assert.Equal(t, []string{"id", "name"}, conn.columns)
+	assert.Equal(t, [][]any{{1, "moe"}, {2, "larry"}}, conn.rows)
+}
+
+func TestCopyFromErr(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	conn := &copyFromerMock{} //nolint:exhaustruct // fields are filled by CopyFrom
+	b := sq.Insert("users").Columns("id", "name").Values(1, "moe")
+
+	for _, q := range []sq.InsertBuilder{
+		sq.Insert("users").Values(1, "moe"),
+		b.Values(2),
+		b.Values(2, sq.Expr("now()")),
+		sq.Insert("users").Columns("id").Select(sq.Select("id").From("src")),
+		b.Suffix("RETURNING id"),
+		b.Prefix("WITH x AS (SELECT 1)"),
+		b.Options("IGNORE"),
+		b.OnConflict().DoNothing(),
+		b.Returning("id"),
+	} {
+		_, err := CopyFrom(ctx, conn, q)
+		require.Error(t, err)
+	}
+	assert.Empty(t, conn.rows)
+}
+
+func TestCopyFromSeq(t *testing.T) {
+	t.Parallel()
+	conn := &copyFromerMock{} //nolint:exhaustruct // fields are filled by CopyFromSeq
+
+	rows := slices.Values([][]any{{1, "moe"}, {2, "larry"}})
+	n, err := CopyFromSeq(context.Background(), conn,
+		sq.Insert("public.users").Columns("id", "name").Values(0, "ignored"), rows)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, pgx.Identifier{"public", "users"}, conn.table)
+	assert.Equal(t, []string{"id", "name"}, conn.columns)
+	assert.Equal(t, [][]any{{1, "moe"}, {2, "larry"}}, conn.rows)
+}
+
+func TestCopyFromSeqErr(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	b := sq.Insert("users").Columns("id", "name")
+
+	conn := &copyFromerMock{} //nolint:exhaustruct // fields are filled by CopyFromSeq
+	_, err := CopyFromSeq(ctx, conn, b, slices.Values([][]any{{1, "moe"}, {2}, {3, "curly"}}))
+	require.EqualError(t, err, "copy from row 1 has 1 values for 2 columns")
+	assert.Equal(t, [][]any{{1, "moe"}}, conn.rows)
+
+	_, err = CopyFromSeq(ctx, conn, b.Returning("id"), slices.Values([][]any{{1, "moe"}}))
+	require.Error(t, err)
+}
@@ -0,0 +1,260 @@
+package squirrel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Recursive CTE helper
+// e.g.
+// tree(id, parent_id) AS (
+//   SELECT id, parent_id FROM nodes WHERE id = ?
+//   UNION ALL
+//   SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id
+// ) SEARCH DEPTH FIRST BY id SET ord CYCLE id SET is_cycle USING path
+
+// defaultDepthColumn is the depth column used by MaxDepth if Depth is not set.
+const defaultDepthColumn = "depth"
+
+type recursiveCteData struct {
+	Name          string
+	Columns       []string
+	Anchor        SelectBuilder
+	Recursive     SelectBuilder
+	Union         bool // UNION instead of UNION ALL
+	SearchBreadth bool
+	SearchBy      []string
+	SearchSet     string
+	CycleColumns  []string
+	CycleSet      string
+	CycleUsing    string
+	DepthColumn   string
+	MaxDepth      *uint64
+	Alias         string // alias of the CTE reference in the recursive term
+}
+
+// recursiveKeyword returns RECURSIVE keyword of WITH clause, which is not used by SQL Server and Oracle.
+func recursiveKeyword(dialect Dialect) string {
+	if dialect == DialectSQLServer || dialect == DialectOracle {
+		return ""
+	}
+	return "RECURSIVE "
+}
+
+// supportsSearchCycle returns true if the dialect supports SEARCH and CYCLE clauses.
+func supportsSearchCycle(dialect Dialect) bool {
+	return dialect == DialectPostgres || dialect == DialectOracle || dialect == DialectUndefined
+}
+
+// depthTerms returns the anchor and recursive terms with the depth counter column
+// and the depth limit condition.
+func (d *recursiveCteData) depthTerms() (anchor, recursive SelectBuilder, columns []string) {
+	anchor, recursive, columns = d.Anchor, d.Recursive, d.Columns
+
+	column := d.DepthColumn
+	if column == "" {
+		if d.MaxDepth == nil {
+			return anchor, recursive, columns
+		}
+		column = defaultDepthColumn
+	}
+
+	ref := d.Name
+	if d.Alias != "" {
+		ref = d.Alias
+	}
+
+	anchor = anchor.Column("0 AS " + column)
+	recursive = recursive.Column(fmt.Sprintf("%s.%s + 1 AS %s", ref, column, column))
+	if d.MaxDepth != nil {
+		recursive = recursive.Where(fmt.Sprintf("%s.%s < ?", ref, column), *d.MaxDepth)
+	}
+	if len(columns) > 0 {
+		columns = append(append([]string{}, columns...), column)
+	}
+	return anchor, recursive, columns
+}
+
+func (d *recursiveCteData) writeSearch(sql *bytes.Buffer, dialect Dialect) error {
+	if len(d.SearchBy) == 0 {
+		return nil
+	}
+	if !supportsSearchCycle(dialect) {
+		return errUnsupported("SEARCH clause", dialect)
+	}
+	if d.SearchSet == "" {
+		return errors.New("search clause of recursive cte must have a set column")
+	}
+
+	order := "DEPTH"
+	if d.SearchBreadth {
+		order = "BREADTH"
+	}
+	_, _ = fmt.Fprintf(sql, " SEARCH %s FIRST BY %s SET %s", order, strings.Join(d.SearchBy, ", "), d.SearchSet)
+	return nil
+}
+
+func (d *recursiveCteData) writeCycle(sql *bytes.Buffer, dialect Dialect) error {
+	if len(d.CycleColumns) == 0 {
+		return nil
+	}
+	if !supportsSearchCycle(dialect) {
+		return errUnsupported("CYCLE clause", dialect)
+	}
+	if d.CycleSet == "" {
+		return errors.New("cycle clause of recursive cte must have a set column")
+	}
+
+	_, _ = fmt.Fprintf(sql, " CYCLE %s SET %s", strings.Join(d.CycleColumns, ", "), d.CycleSet)
+	if dialect == DialectOracle {
+		// Oracle requires the mark values and has no path column
+		_, _ = sql.WriteString(" TO 'Y' DEFAULT 'N'")
+		return nil
+	}
+	if d.CycleUsing == "" {
+		return errors.New("cycle clause of recursive cte must have a using column")
+	}
+	_, _ = fmt.Fprintf(sql, " USING %s", d.CycleUsing)
+	return nil
+}
+
+func (d *recursiveCteData) toSqlDialect(dialect Dialect) (sqlStr string, args []any, err error) {
+	if d.Name == "" {
+		return "", nil, errors.New("recursive cte must have a name")
+	}
+
+	anchor, recursive, columns := d.depthTerms()
+
+	sql := &bytes.Buffer{}
+	_, _ = sql.WriteString(d.Name)
+	if len(columns) > 0 {
+		_, _ = fmt.Fprintf(sql, "(%s)", strings.Join(columns, ", "))
+	}
+	_, _ = sql.WriteString(" AS (")
+
+	if args, err = appendToSql([]Sqlizer{anchor}, sql, "", args); err != nil {
+		return "", nil, err
+	}
+
+	if d.Union {
+		_, _ = sql.WriteString(" UNION ")
+	} else {
+		_, _ = sql.WriteString(" UNION ALL ")
+	}
+
+	if args, err = appendToSql([]Sqlizer{recursive}, sql, "", args); err != nil {
+		return "", nil, err
+	}
+	_, _ = sql.WriteString(")")
+
+	if err = d.writeSearch(sql, dialect); err != nil {
+		return "", nil, err
+	}
+	if err = d.writeCycle(sql, dialect); err != nil {
+		return "", nil, err
+	}
+
+	return sql.String(), args, nil
+}
+
+// Builder
+
+// RecursiveCteBuilder builds recursive CTE (Common Table Expression) of the anchor
+// and the recursive terms. Use it with CommonTableExpressionsBuilder.RecursiveCte
+// or SelectBuilder.WithRecursive.
+type RecursiveCteBuilder builder.Builder
+
+func init() { //nolint:gochecknoinits // required to register RecursiveCteBuilder
+	builder.Register(RecursiveCteBuilder{}, recursiveCteData{}) //nolint:exhaustruct // empty struct is fine
+}
+
+// RecursiveCte returns a new RecursiveCteBuilder with the given name, anchor and recursive terms,
+// which are joined with UNION ALL. The recursive term references the CTE by its name.
+func RecursiveCte(name string, anchor, recursive SelectBuilder) RecursiveCteBuilder {
+	b := builder.Set(RecursiveCteBuilder(builder.EmptyBuilder), "Name", name).(RecursiveCteBuilder)
+	b = builder.Set(b, "Anchor", anchor).(RecursiveCteBuilder)
+	return builder.Set(b, "Recursive", recursive).(RecursiveCteBuilder)
+}
+
+// ToSql builds the CTE "name AS (...)" into a SQL string and bound args.
+func (b RecursiveCteBuilder) ToSql() (sql string, args []any, err error) {
+	return b.toSqlDialect(DialectUndefined)
+}
+
+func (b RecursiveCteBuilder) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	data := builder.GetStruct(b).(recursiveCteData)
+	return data.toSqlDialect(dialect)
+}
+
+// Columns sets the column list of the CTE: "name(a, b) AS (...)".
+func (b RecursiveCteBuilder) Columns(columns ...string) RecursiveCteBuilder {
+	return builder.Set(b, "Columns", columns).(RecursiveCteBuilder)
+}
+
+// Union joins the anchor and the recursive terms with UNION, which removes duplicate rows,
+// instead of UNION ALL.
+func (b RecursiveCteBuilder) Union() RecursiveCteBuilder {
+	return builder.Set(b, "Union", true).(RecursiveCteBuilder)
+}
+
+// UnionAll joins the anchor and the recursive terms with UNION ALL (default).
+func (b RecursiveCteBuilder) UnionAll() RecursiveCteBuilder {
+	return builder.Set(b, "Union", false).(RecursiveCteBuilder)
+}
+
+// SearchDepthFirst adds "SEARCH DEPTH FIRST BY columns SET set" clause (PostgreSQL 14+ and Oracle).
+// The set column can be used to order the result in depth-first order.
+func (b RecursiveCteBuilder) SearchDepthFirst(set string, by ...string) RecursiveCteBuilder {
+	b = builder.Set(b, "SearchBreadth", false).(RecursiveCteBuilder)
+	b = builder.Set(b, "SearchSet", set).(RecursiveCteBuilder)
+	return builder.Set(b, "SearchBy", by).(RecursiveCteBuilder)
+}
+
+// SearchBreadthFirst adds "SEARCH BREADTH FIRST BY columns SET set" clause (PostgreSQL 14+ and Oracle).
+// The set column can be used to order the result in breadth-first order.
+func (b RecursiveCteBuilder) SearchBreadthFirst(set string, by ...string) RecursiveCteBuilder {
+	b = builder.Set(b, "SearchBreadth", true).(RecursiveCteBuilder)
+	b = builder.Set(b, "SearchSet", set).(RecursiveCteBuilder)
+	return builder.Set(b, "SearchBy", by).(RecursiveCteBuilder)
+}
+
+// Cycle adds "CYCLE columns SET set USING using" clause (PostgreSQL 14+), which stops
+// the recursion on the rows already visited by the columns. The set column is true for
+// the cycle rows, the using column holds the path of the visited rows.
+//
+// For Oracle "CYCLE columns SET set TO 'Y' DEFAULT 'N'" is rendered and using is not required.
+// Other dialects return an error, use MaxDepth to limit the recursion.
+func (b RecursiveCteBuilder) Cycle(set, using string, columns ...string) RecursiveCteBuilder {
+	b = builder.Set(b, "CycleSet", set).(RecursiveCteBuilder)
+	b = builder.Set(b, "CycleUsing", using).(RecursiveCteBuilder)
+	return builder.Set(b, "CycleColumns", columns).(RecursiveCteBuilder)
+}
+
+// Depth adds the depth counter column to the CTE: 0 for the anchor rows and
+// "name.column + 1" for the recursive rows. The column is appended to the columns
+// of the terms and the column list of the CTE.
+//
+// If the recursive term references the CTE by an alias, e.g. "JOIN tree t ON ...", set it by Alias.
+func (b RecursiveCteBuilder) Depth(column string) RecursiveCteBuilder {
+	return builder.Set(b, "DepthColumn", column).(RecursiveCteBuilder)
+}
+
+// Alias sets the alias of the CTE reference in the recursive term, which qualifies
+// the depth column of Depth and MaxDepth: Alias("t") for "JOIN tree t ON ..." gives "t.depth + 1".
+// The CTE name is used if not set.
+func (b RecursiveCteBuilder) Alias(alias string) RecursiveCteBuilder {
+	return builder.Set(b, "Alias", alias).(RecursiveCteBuilder)
+}
+
+// MaxDepth limits the recursion depth with "name.depth < max" condition of the recursive
+// term, so rows up to max levels below the anchor rows are returned. It works for all
+// dialects and protects from infinite recursion on cycles.
+//
+// The depth counter column (see Depth) is named "depth" if not set.
+func (b RecursiveCteBuilder) MaxDepth(maxDepth uint64) RecursiveCteBuilder {
+	return builder.Set(b, "MaxDepth", &maxDepth).(RecursiveCteBuilder)
+}
@@ -0,0 +1,138 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTreeCte() RecursiveCteBuilder {
+	return RecursiveCte("tree",
+		Select("id", "parent_id").From("nodes").Where("id = ?", 1),
+		Select("n.id", "n.parent_id").From("nodes n").Join("tree ON n.parent_id = tree.id").Where("n.active = ?", true),
+	).Columns("id", "parent_id")
+}
+
+func TestRecursiveCte(t *testing.T) {
+	t.Parallel()
+	q := WithRecursiveCte(
+		testTreeCte().SearchDepthFirst("ord", "id").Cycle("is_cycle", "path", "id"),
+	).Select(
+		Select("id").From("tree").Where("NOT is_cycle").OrderBy("ord"),
+	).PlaceholderFormat(Dollar)
+
+	sql, args, err := q.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE tree(id, parent_id) AS (" +
+		"SELECT id, parent_id FROM nodes WHERE id = $1 " +
+		"UNION ALL " +
+		"SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id WHERE n.active = $2" +
+		") SEARCH DEPTH FIRST BY id SET ord CYCLE id SET is_cycle USING path " +
+		"SELECT id FROM tree WHERE NOT is_cycle ORDER BY ord"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, true}, args)
+}
+
+func TestRecursiveCteMaxDepth(t *testing.T) {
+	t.Parallel()
+	q := Select("id", "depth").
+		WithRecursive(testTreeCte().Union().MaxDepth(3)).
+		From("tree").
+		Where("depth > ?", 0)
+
+	sql, args, err := q.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE tree(id, parent_id, depth) AS (" +
+		"SELECT id, parent_id, 0 AS depth FROM nodes WHERE id = ? " +
+		"UNION " +
+		"SELECT n.id, n.parent_id, tree.depth + 1 AS depth FROM nodes n JOIN tree ON n.parent_id = tree.id " +
+		"WHERE n.active = ? AND tree.depth < ?" +
+		") SELECT id, depth FROM tree WHERE depth > ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, true, uint64(3), 0}, args)
+
+	sql, _, err = q.PlaceholderFormat(AtP).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WITH tree(id, parent_id, depth) AS (")
+}
+
+func TestRecursiveCteDepth(t *testing.T) {
+	t.Parallel()
+	sql, args, err := RecursiveCte("t",
+		Select("id").From("nodes").Where("parent_id IS NULL"),
+		Select("n.id").From("nodes n").Join("t ON n.parent_id = t.id"),
+	).Depth("lvl").SearchBreadthFirst("ord", "id").ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "t AS (SELECT id, 0 AS lvl FROM nodes WHERE parent_id IS NULL " +
+		"UNION ALL SELECT n.id, t.lvl + 1 AS lvl FROM nodes n JOIN t ON n.parent_id = t.id) " +
+		"SEARCH BREADTH FIRST BY id SET ord"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestRecursiveCteAlias(t *testing.T) {
+	t.Parallel()
+	sql, args, err := RecursiveCte("tree",
+		Select("id").From("nodes").Where("id = ?", 1),
+		Select("n.id").From("nodes n").Join("tree t ON n.parent_id = t.id"),
+	).Alias("t").MaxDepth(2).ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "tree AS (SELECT id, 0 AS depth FROM nodes WHERE id = ? " +
+		"UNION ALL SELECT n.id, t.depth + 1 AS depth FROM nodes n JOIN tree t ON n.parent_id = t.id " +
+		"WHERE t.depth < ?)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, uint64(2)}, args)
+}
+
+func TestRecursiveCteOracle(t *testing.T) {
+	t.Parallel()
+	sql, _, err := WithRecursiveCte(testTreeCte().Cycle("is_cycle", "path", "id")).
+		Select(Select("id").From("tree")).
+		PlaceholderFormat(Colon).
+		ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, sql, "WITH tree(id, parent_id) AS (")
+	assert.Contains(t, sql, ") CYCLE id SET is_cycle TO 'Y' DEFAULT 'N' SELECT id FROM tree")
+}
+
+func TestRecursiveCteErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Select("id").From("tree").
+		WithRecursive(testTreeCte().SearchDepthFirst("ord", "id")).
+		Dialect(DialectMySQL).
+		ToSql()
+	require.EqualError(t, err, "SEARCH clause is not supported by the mysql dialect")
+
+	_, _, err = Select("id").From("tree").
+		WithRecursive(testTreeCte().Cycle("is_cycle", "path", "id")).
+		Dialect(DialectSQLite).
+		ToSql()
+	require.EqualError(t, err, "CYCLE clause is not supported by the sqlite dialect")
+
+	_, _, err = RecursiveCte("", Select("1"), Select("1")).ToSql()
+	require.Error(t, err)
+}
+
+func TestRecursiveCteSearchCycleColumnsErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := testTreeCte().SearchDepthFirst("", "id").ToSql()
+	require.EqualError(t, err, "search clause of recursive cte must have a set column")
+
+	_, _, err = testTreeCte().Cycle("", "path", "id").ToSql()
+	require.EqualError(t, err, "cycle clause of recursive cte must have a set column")
+
+	_, _, err = testTreeCte().Cycle("is_cycle", "", "id").ToSql()
+	require.EqualError(t, err, "cycle clause of recursive cte must have a using column")
+
+	// Oracle has no path column
+	_, _, err = WithRecursiveCte(testTreeCte().Cycle("is_cycle", "", "id")).
+		Select(Select("id").From("tree")).
+		PlaceholderFormat(Colon).
+		ToSql()
+	require.NoError(t, err)
+}
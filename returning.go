@@ -0,0 +1,127 @@
+package squirrel
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RETURNING clause of INSERT, UPDATE and DELETE statements
+// e.g.
+// UPDATE t SET name = ? WHERE id = ? RETURNING id, old.name, new.name
+//
+// SQL Server uses OUTPUT clause instead, which is placed before the source of the rows
+// e.g.
+// UPDATE t SET name = ? OUTPUT inserted.id, deleted.name, inserted.name WHERE id = ?
+
+// Pseudo tables of SQL Server OUTPUT clause.
+const (
+	outputInserted = "inserted"
+	outputDeleted  = "deleted"
+)
+
+var plainIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`) //nolint:gochecknoglobals // compiled once
+
+// dialectSqlizer is implemented by expressions whose SQL depends on the dialect.
+type dialectSqlizer interface {
+	toSqlDialect(dialect Dialect) (sql string, args []any, err error)
+}
+
+// rowVersionRef references the value of a column before (OLD) or after (NEW) the change.
+type rowVersionRef struct {
+	column string
+	old    bool
+}
+
+// Old references the value of the column before the change in RETURNING clause
+// (PostgreSQL 18+ "old.column", SQL Server "deleted.column").
+func Old(column string) Sqlizer {
+	return rowVersionRef{column: column, old: true}
+}
+
+// New references the value of the column after the change in RETURNING clause
+// (PostgreSQL 18+ "new.column", SQL Server "inserted.column").
+func New(column string) Sqlizer {
+	return rowVersionRef{column: column, old: false}
+}
+
+// ToSql builds the reference in PostgreSQL syntax.
+func (r rowVersionRef) ToSql() (sql string, args []any, err error) {
+	return r.toSqlDialect(DialectPostgres)
+}
+
+func (r rowVersionRef) toSqlDialect(dialect Dialect) (sql string, args []any, err error) {
+	switch dialect { //nolint:exhaustive // other dialects do not support OLD/NEW references
+	case DialectPostgres, DialectUndefined:
+		if r.old {
+			return "old." + r.column, nil, nil
+		}
+		return "new." + r.column, nil, nil
+	case DialectSQLServer:
+		if r.old {
+			return outputDeleted + "." + r.column, nil, nil
+		}
+		return outputInserted + "." + r.column, nil, nil
+	}
+
+	return "", nil, errUnsupported("OLD/NEW reference", dialect)
+}
+
+// checkReturning checks that the dialect supports RETURNING clause.
+func checkReturning(dialect Dialect, columns []any) error {
+	if len(columns) == 0 {
+		return nil
+	}
+
+	switch dialect { //nolint:exhaustive // other dialects support RETURNING or OUTPUT
+	case DialectMySQL, DialectOracle:
+		return errUnsupported("RETURNING", dialect)
+	}
+
+	return nil
+}
+
+// returningColumnSQL builds a column of RETURNING clause. Plain column names
+// are qualified with the pseudo table for SQL Server OUTPUT clause.
+func returningColumnSQL(column any, dialect Dialect, pseudoTable string) (sql string, args []any, err error) {
+	switch c := column.(type) {
+	case string:
+		if dialect == DialectSQLServer && (c == "*" || plainIdentifierRegexp.MatchString(c)) {
+			return pseudoTable + "." + c, nil, nil
+		}
+		return c, nil, nil
+	case dialectSqlizer:
+		return c.toSqlDialect(dialect)
+	case Sqlizer:
+		return nestedToSql(c)
+	}
+
+	return "", nil, fmt.Errorf("returning column must be a string or Sqlizer, got %T", column)
+}
+
+// writeReturning writes RETURNING clause, or OUTPUT clause for SQL Server.
+func writeReturning(sql *bytes.Buffer, columns []any, dialect Dialect, pseudoTable string, args []any) ([]any, error) {
+	if len(columns) == 0 {
+		return args, nil
+	}
+
+	if dialect == DialectSQLServer {
+		_, _ = sql.WriteString(" OUTPUT ")
+	} else {
+		_, _ = sql.WriteString(" RETURNING ")
+	}
+
+	colSqls := make([]string, len(columns))
+	for i, column := range columns {
+		colSql, colArgs, err := returningColumnSQL(column, dialect, pseudoTable)
+		if err != nil {
+			return nil, err
+		}
+		colSqls[i] = colSql
+		args = append(args, colArgs...)
+	}
+	_, _ = sql.WriteString(strings.Join(colSqls, ", "))
+
+	return args, nil
+}
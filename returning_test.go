@@ -0,0 +1,80 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertReturning(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("users").Columns("id", "name").Values(1, "moe").
+		OnConflict("id").DoUpdate().SetExcluded("name").End().
+		Returning("id", New("name")).
+		Suffix("-- upsert").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES ($1,$2) "+
+		"ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name RETURNING id, new.name -- upsert", sql)
+	assert.Equal(t, []any{1, "moe"}, args)
+
+	sql, _, err = Insert("users").Columns("id", "name").Values(1, "moe").
+		Returning("id", "*", "name AS n").
+		PlaceholderFormat(AtP).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) OUTPUT inserted.id, inserted.*, name AS n VALUES (@p1,@p2)", sql)
+}
+
+func TestUpdateReturning(t *testing.T) {
+	t.Parallel()
+	b := Update("users").Set("name", "larry").Where("id = ?", 1).Returning("id", Old("name"), New("name"))
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = $1 WHERE id = $2 RETURNING id, old.name, new.name", sql)
+	assert.Equal(t, []any{"larry", 1}, args)
+
+	sql, _, err = b.Dialect(DialectSQLServer).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? OUTPUT inserted.id, deleted.name, inserted.name WHERE id = ?", sql)
+
+	sql, _, err = Update("users").Set("name", "larry").Where("id = ?", 1).
+		OrderBy("id").Limit(1).Returning("id").Dialect(DialectSQLite).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ? RETURNING id ORDER BY id LIMIT 1", sql)
+}
+
+func TestDeleteReturning(t *testing.T) {
+	t.Parallel()
+	b := Delete("users").Where("id = ?", 1).Returning("*")
+
+	sql, _, err := b.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? RETURNING *", sql)
+
+	sql, _, err = b.PlaceholderFormat(AtP).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users OUTPUT deleted.* WHERE id = @p1", sql)
+}
+
+func TestReturningErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Delete("users").Returning("id").Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "RETURNING is not supported by the mysql dialect")
+
+	_, _, err = Update("users").Set("a", 1).Returning("id").PlaceholderFormat(Colon).ToSql()
+	require.EqualError(t, err, "RETURNING is not supported by the oracle dialect")
+
+	_, _, err = Insert("users").Values(1).Ignore().Returning("id").ToSql()
+	require.Error(t, err)
+
+	_, _, err = Update("users").Set("a", 1).Where("id = ?", 1).Returning(Old("a")).Dialect(DialectSQLite).ToSql()
+	require.EqualError(t, err, "OLD/NEW reference is not supported by the sqlite dialect")
+
+	_, _, err = Delete("users").Returning(1).ToSql()
+	require.Error(t, err)
+}
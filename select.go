@@ -97,7 +97,10 @@ type OrderCond struct {
 
 type selectData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	Prefixes          []Sqlizer
+	Ctes              []Sqlizer
+	RecursiveCtes     bool // WITH RECURSIVE
 	Options           []string
 	Columns           []Sqlizer
 	From              Sqlizer
@@ -106,11 +109,16 @@ type selectData struct {
 	GroupBys          []string
 	HavingParts       []Sqlizer
 	OrderByParts      []Sqlizer
-	Limit             string
-	Offset            string
+	Limit             *uint64
+	Offset            *uint64
+	Fetch             *uint64
+	FetchWithTies     bool
+	BindLimitOffset   bool // LIMIT, OFFSET and FETCH values are passed as args.
 	Suffixes          []Sqlizer
 	Paginator         Paginator
 	IDColumn          string // ID column name. Required for pagination by ID.
+	SoftDeletes       []softDeleteTable
+	SoftDeleteScope   softDeleteScope
 }
 
 func (d *selectData) ToSql() (sqlStr string, args []any, err error) {
@@ -137,6 +145,27 @@ func (d *selectData) writePrefixes(sql *bytes.Buffer, args []any) ([]any, error)
 	return args, nil
 }
 
+func (d *selectData) writeWithClause(sql *bytes.Buffer, args []any) ([]any, error) {
+	if len(d.Ctes) == 0 {
+		return args, nil
+	}
+
+	dialect := resolveDialect(d.Dialect, d.PlaceholderFormat)
+
+	_, _ = sql.WriteString("WITH ")
+	if d.RecursiveCtes {
+		_, _ = sql.WriteString(recursiveKeyword(dialect))
+	}
+
+	args, err := writeCtes(sql, d.Ctes, dialect, args)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = sql.WriteString(" ")
+	return args, nil
+}
+
 func (d *selectData) writeSelectClause(sql *bytes.Buffer, args []any) ([]any, error) {
 	_, _ = sql.WriteString("SELECT ")
 
@@ -219,38 +248,90 @@ func (d *selectData) writeOrderByClause(sql *bytes.Buffer, args []any) ([]any, e
 	return appendToSql(d.OrderByParts, sql, ", ", args)
 }
 
-func (d *selectData) writeLimitOffset(sql *bytes.Buffer) error {
-	if d.Limit != "" {
+// writeLimitValue writes " <keyword> n", binding n as arg if bind is set.
+func writeLimitValue(sql *bytes.Buffer, keyword string, n uint64, bind bool, args []any) []any {
+	_, _ = sql.WriteString(" ")
+	_, _ = sql.WriteString(keyword)
+
+	if bind {
+		_, _ = sql.WriteString(" ?")
+		return append(args, n)
+	}
+
+	_, _ = sql.WriteString(" ")
+	_, _ = sql.WriteString(strconv.FormatUint(n, 10))
+	return args
+}
+
+func (d *selectData) writeLimitOffset(sql *bytes.Buffer, args []any) ([]any, error) {
+	if d.Limit != nil {
 		if d.Paginator.pType != PaginatorTypeUndefined {
-			return errors.New("limit and paginator cannot be used together")
+			return nil, errors.New("limit and paginator cannot be used together")
+		}
+		if d.Fetch != nil {
+			return nil, errors.New("limit and fetch first cannot be used together")
 		}
-		_, _ = sql.WriteString(" LIMIT ")
-		_, _ = sql.WriteString(d.Limit)
+		args = writeLimitValue(sql, "LIMIT", *d.Limit, d.BindLimitOffset, args)
 	}
 
-	if d.Offset != "" {
+	if d.Offset != nil {
 		if d.Paginator.pType != PaginatorTypeUndefined {
-			return errors.New("offset and paginator cannot be used together")
+			return nil, errors.New("offset and paginator cannot be used together")
+		}
+		args = writeLimitValue(sql, "OFFSET", *d.Offset, d.BindLimitOffset, args)
+		if d.Fetch != nil {
+			_, _ = sql.WriteString(" ROWS")
 		}
-		_, _ = sql.WriteString(" OFFSET ")
-		_, _ = sql.WriteString(d.Offset)
 	}
 
-	return nil
+	return args, nil
 }
 
-func (d *selectData) writePagination(sql *bytes.Buffer) {
+func (d *selectData) writeFetch(sql *bytes.Buffer, args []any) ([]any, error) {
+	if d.Fetch == nil {
+		return args, nil
+	}
+	if d.Paginator.pType != PaginatorTypeUndefined {
+		return nil, errors.New("fetch first and paginator cannot be used together")
+	}
+	if d.FetchWithTies && len(d.OrderByParts) == 0 {
+		return nil, errors.New("fetch first with ties requires ORDER BY clause")
+	}
+
+	switch dialect := resolveDialect(d.Dialect, d.PlaceholderFormat); dialect {
+	case DialectMySQL, DialectSQLite:
+		return nil, errUnsupported("FETCH FIRST", dialect)
+	case DialectSQLServer:
+		if d.FetchWithTies {
+			return nil, errUnsupported("FETCH FIRST WITH TIES", dialect)
+		}
+	case DialectPostgres, DialectOracle, DialectUndefined:
+	}
+
+	args = writeLimitValue(sql, "FETCH FIRST", *d.Fetch, d.BindLimitOffset, args)
+	if d.FetchWithTies {
+		_, _ = sql.WriteString(" ROWS WITH TIES")
+	} else {
+		_, _ = sql.WriteString(" ROWS ONLY")
+	}
+
+	return args, nil
+}
+
+func (d *selectData) writePagination(sql *bytes.Buffer, args []any) []any {
 	switch d.Paginator.pType {
 	case PaginatorTypeUndefined:
 		// No pagination
 	case PaginatorTypeByPage:
-		_, _ = fmt.Fprintf(sql, " LIMIT %d", d.Paginator.limit)
+		args = writeLimitValue(sql, "LIMIT", d.Paginator.limit, d.BindLimitOffset, args)
 		if d.Paginator.page > 1 {
-			_, _ = fmt.Fprintf(sql, " OFFSET %d", d.Paginator.limit*(d.Paginator.page-1))
+			args = writeLimitValue(sql, "OFFSET", d.Paginator.limit*(d.Paginator.page-1), d.BindLimitOffset, args)
 		}
 	case PaginatorTypeByID:
-		_, _ = fmt.Fprintf(sql, " LIMIT %d", d.Paginator.limit)
+		args = writeLimitValue(sql, "LIMIT", d.Paginator.limit, d.BindLimitOffset, args)
 	}
+
+	return args
 }
 
 func (d *selectData) writeSuffixes(sql *bytes.Buffer, args []any) ([]any, error) {
@@ -267,12 +348,20 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 		return "", nil, errors.New("select statements must have at least one result column")
 	}
 
+	if d, err = d.applySoftDelete(); err != nil {
+		return "", nil, err
+	}
+
 	sql := &bytes.Buffer{}
 
 	if args, err = d.writePrefixes(sql, args); err != nil {
 		return "", nil, err
 	}
 
+	if args, err = d.writeWithClause(sql, args); err != nil {
+		return "", nil, err
+	}
+
 	if args, err = d.writeSelectClause(sql, args); err != nil {
 		return "", nil, err
 	}
@@ -299,11 +388,15 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []any, err error) {
 		return "", nil, err
 	}
 
-	if err := d.writeLimitOffset(sql); err != nil {
+	if args, err = d.writeLimitOffset(sql, args); err != nil {
+		return "", nil, err
+	}
+
+	if args, err = d.writeFetch(sql, args); err != nil {
 		return "", nil, err
 	}
 
-	d.writePagination(sql)
+	args = d.writePagination(sql, args)
 
 	if args, err = d.writeSuffixes(sql, args); err != nil {
 		return "", nil, err
@@ -329,6 +422,12 @@ func (b SelectBuilder) PlaceholderFormat(f PlaceholderFormat) SelectBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(SelectBuilder)
 }
 
+// Dialect sets the SQL dialect of the query. If not set, the dialect is
+// detected by the PlaceholderFormat.
+func (b SelectBuilder) Dialect(d Dialect) SelectBuilder {
+	return builder.Set(b, "Dialect", d).(SelectBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -407,9 +506,17 @@ func (b SelectBuilder) FromSelect(from SelectBuilder, alias string) SelectBuilde
 	return builder.Set(b, "From", Alias(from, alias)).(SelectBuilder)
 }
 
+// FromValues sets a VALUES list into the FROM clause of the query.
+// The list must have an alias, see ValuesBuilder.As.
+func (b SelectBuilder) FromValues(from ValuesBuilder) SelectBuilder {
+	return builder.Set(b, "From", valuesTable{from}).(SelectBuilder)
+}
+
 // JoinClause adds a join clause to the query.
+// Sqlizer args of a string clause are expanded in place of their placeholders,
+// e.g. JoinClause("JOIN ? ON t.id = v.id", Values(rows).As("v", "id")).
 func (b SelectBuilder) JoinClause(pred any, args ...any) SelectBuilder {
-	return builder.Append(b, "Joins", newPart(pred, args...)).(SelectBuilder)
+	return builder.Append(b, "Joins", newJoinPart(pred, args...)).(SelectBuilder)
 }
 
 // Join adds a JOIN clause to the query.
@@ -596,7 +703,7 @@ func (b SelectBuilder) SetIDColumn(column string) SelectBuilder {
 
 // Limit sets a LIMIT clause on the query.
 func (b SelectBuilder) Limit(limit uint64) SelectBuilder {
-	return builder.Set(b, "Limit", strconv.FormatUint(limit, 10)).(SelectBuilder)
+	return builder.Set(b, "Limit", &limit).(SelectBuilder)
 }
 
 // RemoveLimit removes LIMIT clause allowing access to all records.
@@ -605,8 +712,9 @@ func (b SelectBuilder) RemoveLimit() SelectBuilder {
 }
 
 // Offset sets a OFFSET clause on the query.
+// Combined with FetchFirst it is rendered as "OFFSET n ROWS".
 func (b SelectBuilder) Offset(offset uint64) SelectBuilder {
-	return builder.Set(b, "Offset", strconv.FormatUint(offset, 10)).(SelectBuilder)
+	return builder.Set(b, "Offset", &offset).(SelectBuilder)
 }
 
 // RemoveOffset removes OFFSET clause.
@@ -614,6 +722,34 @@ func (b SelectBuilder) RemoveOffset() SelectBuilder {
 	return builder.Delete(b, "Offset").(SelectBuilder)
 }
 
+// FetchFirst sets a "FETCH FIRST n ROWS ONLY" clause on the query.
+// It is the SQL standard alternative to LIMIT and can't be combined with it.
+// It is not supported by MySQL and SQLite dialects.
+func (b SelectBuilder) FetchFirst(n uint64) SelectBuilder {
+	b = builder.Set(b, "Fetch", &n).(SelectBuilder)
+	return builder.Set(b, "FetchWithTies", false).(SelectBuilder)
+}
+
+// FetchFirstWithTies sets a "FETCH FIRST n ROWS WITH TIES" clause on the query.
+// The query must have ORDER BY clause. It is not supported by MySQL, SQLite and SQL Server dialects.
+func (b SelectBuilder) FetchFirstWithTies(n uint64) SelectBuilder {
+	b = builder.Set(b, "Fetch", &n).(SelectBuilder)
+	return builder.Set(b, "FetchWithTies", true).(SelectBuilder)
+}
+
+// RemoveFetch removes FETCH FIRST clause.
+func (b SelectBuilder) RemoveFetch() SelectBuilder {
+	b = builder.Delete(b, "Fetch").(SelectBuilder)
+	return builder.Delete(b, "FetchWithTies").(SelectBuilder)
+}
+
+// BindLimitOffset makes LIMIT, OFFSET, FETCH FIRST and paginator values to be
+// passed as args instead of being printed into the SQL string. This way all
+// pages of a query share the same SQL text, e.g. for prepared statement caches.
+func (b SelectBuilder) BindLimitOffset(bind bool) SelectBuilder {
+	return builder.Set(b, "BindLimitOffset", bind).(SelectBuilder)
+}
+
 // Suffix adds an expression to the end of the query.
 func (b SelectBuilder) Suffix(sql string, args ...any) SelectBuilder {
 	return b.SuffixExpr(Expr(sql, args...))
@@ -693,7 +829,26 @@ func (b SelectBuilder) Alias(table string, prefix ...string) alias {
 	}
 }
 
-// With adds a CTE (Common Table Expression) to the query.
-func (b SelectBuilder) With(cteName string, cte SelectBuilder) SelectBuilder {
-	return b.PrefixExpr(cte.Prefix(fmt.Sprintf("WITH %s AS (", cteName)).Suffix(")"))
+// With adds a CTE (Common Table Expression) to WITH clause of the query.
+// Optional columns are rendered as the column list: "WITH cte(a, b) AS (...) SELECT ...".
+func (b SelectBuilder) With(cteName string, cte SelectBuilder, columns ...string) SelectBuilder {
+	return b.WithCte(Cte(cte, cteName, columns...))
+}
+
+// WithCte adds CTEs created by Cte to WITH clause of the query, e.g.
+//
+//	Select("*").From("t").WithCte(Cte(sub, "t", "a", "b").Materialized())
+func (b SelectBuilder) WithCte(ctes ...cteExpr) SelectBuilder {
+	for _, cte := range ctes {
+		b = builder.Append(b, "Ctes", cte).(SelectBuilder)
+	}
+	return b
+}
+
+// WithRecursive adds the recursive CTE to WITH clause of the query and sets the RECURSIVE option.
+//
+// See RecursiveCte for more information.
+func (b SelectBuilder) WithRecursive(cte RecursiveCteBuilder) SelectBuilder {
+	b = builder.Set(b, "RecursiveCtes", true).(SelectBuilder)
+	return builder.Append(b, "Ctes", cte).(SelectBuilder)
 }
@@ -567,5 +567,119 @@ func TestSelectWith(t *testing.T) {
 
 	sql, _, err := q.ToSql()
 	require.NoError(t, err)
-	assert.Equal(t, "WITH table1 AS ( SELECT a FROM table2 ) SELECT a FROM table3", sql)
+	assert.Equal(t, "WITH table1 AS (SELECT a FROM table2) SELECT a FROM table3", sql)
+}
+
+func TestSelectWithMultipleCtes(t *testing.T) {
+	t.Parallel()
+	q := Select("a.x", "b.y").
+		Prefix("/* report */").
+		With("a", Select("x").From("t1").Where("x > ?", 1)).
+		With("b", Select("id", "y").From("t2").Where("y < ?", 2), "id", "y").
+		WithCte(Cte(Select("z").From("t3"), "c").NotMaterialized()).
+		From("a").
+		Join("b ON b.id = a.x").
+		Where("a.x <> ?", 3).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := q.ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "/* report */ WITH a AS (SELECT x FROM t1 WHERE x > $1), " +
+		"b(id, y) AS (SELECT id, y FROM t2 WHERE y < $2), " +
+		"c AS NOT MATERIALIZED (SELECT z FROM t3) " +
+		"SELECT a.x, b.y FROM a JOIN b ON b.id = a.x WHERE a.x <> $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{1, 2, 3}, args)
+
+	_, _, err = q.PlaceholderFormat(AtP).ToSql()
+	require.EqualError(t, err, "CTE MATERIALIZED hint is not supported by the sqlserver dialect")
+}
+
+func TestSelectBindLimitOffset(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Select("id").From("users").Where("a = ?", 1).
+		Limit(10).Offset(20).
+		BindLimitOffset(true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE a = $1 LIMIT $2 OFFSET $3", sql)
+	assert.Equal(t, []any{1, uint64(10), uint64(20)}, args)
+
+	psql := StatementBuilder.PlaceholderFormat(Dollar).BindLimitOffset(true)
+
+	page2, args2, err := psql.Select("id").From("users").OrderBy("id").Paginate(PaginatorByPage(10, 2)).ToSql()
+	require.NoError(t, err)
+	page3, args3, err := psql.Select("id").From("users").OrderBy("id").Paginate(PaginatorByPage(10, 3)).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY id LIMIT $1 OFFSET $2", page2)
+	assert.Equal(t, page2, page3)
+	assert.Equal(t, []any{uint64(10), uint64(10)}, args2)
+	assert.Equal(t, []any{uint64(10), uint64(20)}, args3)
+
+	sql, args, err = psql.Select("id").From("users").OrderBy("id").
+		Paginate(PaginatorByID(5, 100)).SetIDColumn("id").ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2", sql)
+	assert.Equal(t, []any{int64(100), uint64(5)}, args)
+
+	sql, args, err = psql.Update("users").Set("a", 1).Limit(3).AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET a = $1 WHERE ctid IN (SELECT ctid FROM users LIMIT $2)", sql)
+	assert.Equal(t, []any{1, uint64(3)}, args)
+
+	sql, args, err = psql.Delete("users").Limit(3).Offset(1).AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE ctid IN (SELECT ctid FROM users LIMIT $1 OFFSET $2)", sql)
+	assert.Equal(t, []any{uint64(3), uint64(1)}, args)
+
+	sql, _, err = psql.Insert("users").Columns("a").Values(1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (a) VALUES ($1)", sql)
+}
+
+func TestSelectFetchFirst(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Select("id").From("users").OrderBy("score DESC").FetchFirst(10).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY score DESC FETCH FIRST 10 ROWS ONLY", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Select("id").From("users").OrderBy("score DESC").
+		Offset(20).FetchFirstWithTies(10).BindLimitOffset(true).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY score DESC OFFSET ? ROWS FETCH FIRST ? ROWS WITH TIES", sql)
+	assert.Equal(t, []any{uint64(20), uint64(10)}, args)
+
+	sql, _, err = Select("id").From("users").FetchFirst(10).RemoveFetch().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+
+	_, _, err = Select("id").From("users").FetchFirstWithTies(10).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Select("id").From("users").Limit(5).FetchFirst(10).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Select("id").From("users").Paginate(PaginatorByPage(10, 1)).FetchFirst(10).ToSql()
+	require.Error(t, err)
+}
+
+func TestSelectFetchFirstDialects(t *testing.T) {
+	t.Parallel()
+	b := Select("id").From("users").OrderBy("score DESC").FetchFirst(3)
+
+	_, _, err := b.Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "FETCH FIRST is not supported by the mysql dialect")
+
+	_, _, err = b.Dialect(DialectSQLite).ToSql()
+	require.EqualError(t, err, "FETCH FIRST is not supported by the sqlite dialect")
+
+	_, _, err = b.FetchFirstWithTies(3).Dialect(DialectSQLServer).ToSql()
+	require.EqualError(t, err, "FETCH FIRST WITH TIES is not supported by the sqlserver dialect")
+
+	sql, _, err := b.Dialect(DialectOracle).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY score DESC FETCH FIRST 3 ROWS ONLY", sql)
 }
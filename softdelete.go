@@ -0,0 +1,272 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// Soft delete scopes: rows of the configured tables are marked as deleted by the
+// marker column instead of being removed, e.g.
+// DELETE FROM t WHERE id = ?  ->  UPDATE t SET deleted_at = now() WHERE deleted_at IS NULL AND id = ?
+// SELECT * FROM t              ->  SELECT * FROM t WHERE deleted_at IS NULL
+
+// softDeleteTable is a table with the soft delete marker column.
+type softDeleteTable struct {
+	table  string
+	column string
+}
+
+// softDeleteScope selects the rows of soft delete tables.
+type softDeleteScope int
+
+const (
+	softDeleteActive      softDeleteScope = iota // not deleted rows only
+	softDeleteWithDeleted                        // all rows
+	softDeleteOnlyDeleted                        // deleted rows only
+)
+
+// joinOnRegexp finds ON keyword of the join clause.
+var joinOnRegexp = regexp.MustCompile(`(?i)\sON\s`) //nolint:gochecknoglobals // compiled once
+
+// findSoftDelete returns the marker column and the reference name of the table,
+// e.g. "o" for "orders o", if the table is configured for soft delete.
+func findSoftDelete(tables []softDeleteTable, table string) (ref, column string, ok bool) {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return "", "", false
+	}
+
+	for _, t := range tables {
+		if strings.EqualFold(t.table, fields[0]) {
+			return tableRef(table), t.column, true
+		}
+	}
+	return "", "", false
+}
+
+// softDeleteCond returns the marker column condition.
+func softDeleteCond(ref, column string, qualify, deleted bool) string {
+	if qualify {
+		column = ref + "." + column
+	}
+	if deleted {
+		return column + " IS NOT NULL"
+	}
+	return column + " IS NULL"
+}
+
+// plainTable returns the table of From clause set by the table name.
+func plainTable(from Sqlizer) (string, bool) {
+	p, ok := from.(*part)
+	if !ok {
+		return "", false
+	}
+	table, ok := p.pred.(string)
+	return table, ok
+}
+
+// softDeleteJoin adds the marker column condition of the joined table to ON clause
+// of the join, or returns it if the join has no ON clause.
+func softDeleteJoin(tables []softDeleteTable, join Sqlizer) (res Sqlizer, where string, err error) {
+	sql, args, err := nestedToSql(join)
+	if err != nil {
+		return nil, "", err
+	}
+
+	words := strings.Fields(sql)
+	i := slices.IndexFunc(words, func(w string) bool { return strings.EqualFold(w, "JOIN") })
+	if i < 0 || i+1 >= len(words) {
+		return join, "", nil
+	}
+
+	table := words[i+1]
+	if rest := words[i+2:]; len(rest) > 0 {
+		alias := rest[0]
+		if strings.EqualFold(alias, "AS") && len(rest) > 1 {
+			alias = rest[1]
+		}
+		if !strings.EqualFold(alias, "ON") && !strings.EqualFold(alias, "USING") {
+			table += " " + alias
+		}
+	}
+
+	ref, column, ok := findSoftDelete(tables, table)
+	if !ok {
+		return join, "", nil
+	}
+	cond := softDeleteCond(ref, column, true, false)
+
+	if loc := joinOnRegexp.FindStringIndex(sql); loc != nil {
+		sql = fmt.Sprintf("%s ON (%s) AND %s", sql[:loc[0]], sql[loc[1]:], cond)
+		return newPart(sql, args...), "", nil
+	}
+
+	kind := strings.ToUpper(strings.Join(words[:i], " "))
+	if strings.Contains(kind, "LEFT") || strings.Contains(kind, "RIGHT") || strings.Contains(kind, "FULL") {
+		return nil, "", fmt.Errorf("soft delete condition of %s cannot be added to outer join without ON clause", table)
+	}
+	return join, cond, nil
+}
+
+// softDeleteFilters returns the joins and WHERE conditions with the marker column
+// conditions of the main table, the from table and the joined tables.
+func softDeleteFilters(
+	tables []softDeleteTable, scope softDeleteScope, table string, from Sqlizer, joins []Sqlizer, qualify bool,
+) (resJoins, where []Sqlizer, err error) {
+	if ref, column, ok := findSoftDelete(tables, table); ok {
+		where = append(where, Expr(softDeleteCond(ref, column, qualify, scope == softDeleteOnlyDeleted)))
+	}
+
+	if fromTable, ok := plainTable(from); ok {
+		if ref, column, ok := findSoftDelete(tables, fromTable); ok {
+			where = append(where, Expr(softDeleteCond(ref, column, true, false)))
+		}
+	}
+
+	resJoins = make([]Sqlizer, len(joins))
+	for i, join := range joins {
+		var cond string
+		if resJoins[i], cond, err = softDeleteJoin(tables, join); err != nil {
+			return nil, nil, err
+		}
+		if cond != "" {
+			where = append(where, Expr(cond))
+		}
+	}
+
+	return resJoins, where, nil
+}
+
+// applySoftDelete returns a copy of the select data with the soft delete conditions.
+func (d *selectData) applySoftDelete() (*selectData, error) {
+	if len(d.SoftDeletes) == 0 || d.SoftDeleteScope == softDeleteWithDeleted {
+		return d, nil
+	}
+
+	table, _ := plainTable(d.From)
+	joins, where, err := softDeleteFilters(d.SoftDeletes, d.SoftDeleteScope, table, nil, d.Joins, len(d.Joins) > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	res := *d
+	res.Joins = joins
+	res.WhereParts = append(where, d.WhereParts...)
+	return &res, nil
+}
+
+// applySoftDelete returns a copy of the update data with the soft delete conditions.
+func (d *updateData) applySoftDelete() (*updateData, error) {
+	if len(d.SoftDeletes) == 0 || d.SoftDeleteScope == softDeleteWithDeleted {
+		return d, nil
+	}
+
+	qualify := d.From != nil || len(d.Joins) > 0 || d.ValuesFrom != nil
+	joins, where, err := softDeleteFilters(d.SoftDeletes, d.SoftDeleteScope, d.Table, d.From, d.Joins, qualify)
+	if err != nil {
+		return nil, err
+	}
+
+	res := *d
+	res.Joins = joins
+	res.WhereParts = append(where, d.WhereParts...)
+	return &res, nil
+}
+
+// onlyDeleted returns true if the query updates the deleted rows of soft delete table only.
+// The marker column condition filters the rows, so the full table guard allows the query.
+func (d *updateData) onlyDeleted() bool {
+	_, _, ok := findSoftDelete(d.SoftDeletes, d.Table)
+	return ok && d.SoftDeleteScope == softDeleteOnlyDeleted
+}
+
+// softDeleteUpdate returns UPDATE statement marking the rows as deleted
+// if the table is configured for soft delete.
+func (d *deleteData) softDeleteUpdate() (*updateData, bool, error) {
+	if d.HardDelete {
+		return nil, false, nil
+	}
+
+	_, column, ok := findSoftDelete(d.SoftDeletes, d.From)
+	if !ok {
+		return nil, false, nil
+	}
+
+	if len(d.Using) > 0 || len(d.Joins) > 0 {
+		return nil, false, errors.New("soft delete cannot be used with Using clause or joins, use HardDelete")
+	}
+
+	//nolint:exhaustruct // other fields are not used by DELETE
+	return &updateData{
+		PlaceholderFormat: d.PlaceholderFormat,
+		Dialect:           d.Dialect,
+		Prefixes:          d.Prefixes,
+		Table:             d.From,
+		SetClauses:        []setClause{{column: column, value: Now()}},
+		WhereParts:        d.WhereParts,
+		OrderBys:          d.OrderBys,
+		Limit:             d.Limit,
+		Offset:            d.Offset,
+		BindLimitOffset:   d.BindLimitOffset,
+		LimitKey:          d.LimitKey,
+		Returning:         d.Returning,
+		Suffixes:          d.Suffixes,
+		SoftDeletes:       d.SoftDeletes,
+		AllowFullTable:    d.AllowFullTable,
+	}, true, nil
+}
+
+// SoftDelete configures soft delete of the table: DELETE statements of the table
+// set the marker column to the current timestamp (see Now), and SELECT and UPDATE
+// statements select the rows where the marker column is NULL.
+//
+// The condition is added to WHERE clause for the main table of the query and to ON clause
+// for the joined tables (WHERE clause for inner joins without ON). The table is matched
+// by name, so aliases are supported: "orders o" gives "o.deleted_at IS NULL".
+func (b StatementBuilderType) SoftDelete(table, column string) StatementBuilderType {
+	return builder.Append(b, "SoftDeletes", softDeleteTable{table: table, column: column}).(StatementBuilderType)
+}
+
+// WithDeleted disables soft delete conditions, so the query selects deleted rows too.
+//
+// See StatementBuilderType.SoftDelete for more information.
+func (b SelectBuilder) WithDeleted() SelectBuilder {
+	return builder.Set(b, "SoftDeleteScope", softDeleteWithDeleted).(SelectBuilder)
+}
+
+// OnlyDeleted makes the query to select deleted rows of the main table only.
+// Joined tables are still filtered by the soft delete conditions.
+//
+// See StatementBuilderType.SoftDelete for more information.
+func (b SelectBuilder) OnlyDeleted() SelectBuilder {
+	return builder.Set(b, "SoftDeleteScope", softDeleteOnlyDeleted).(SelectBuilder)
+}
+
+// WithDeleted disables soft delete conditions, so the query updates deleted rows too.
+//
+// See StatementBuilderType.SoftDelete for more information.
+func (b UpdateBuilder) WithDeleted() UpdateBuilder {
+	return builder.Set(b, "SoftDeleteScope", softDeleteWithDeleted).(UpdateBuilder)
+}
+
+// OnlyDeleted makes the query to update deleted rows of the main table only,
+// e.g. to restore them: Update("t").Set("deleted_at", nil).OnlyDeleted().
+// The condition counts as a filter of the full table guard (see ErrFullTable),
+// so restoring all deleted rows does not need AllowFullTable.
+//
+// See StatementBuilderType.SoftDelete for more information.
+func (b UpdateBuilder) OnlyDeleted() UpdateBuilder {
+	return builder.Set(b, "SoftDeleteScope", softDeleteOnlyDeleted).(UpdateBuilder)
+}
+
+// HardDelete makes the query to delete the rows of soft delete table physically.
+//
+// See StatementBuilderType.SoftDelete for more information.
+func (b DeleteBuilder) HardDelete() DeleteBuilder {
+	return builder.Set(b, "HardDelete", true).(DeleteBuilder)
+}
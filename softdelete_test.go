@@ -0,0 +1,108 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDeleteSelect(t *testing.T) {
+	t.Parallel()
+	sb := StatementBuilder.SoftDelete("users", "deleted_at").SoftDelete("orders", "removed_at")
+
+	sql, args, err := sb.Select("*").From("users").Where("id = ?", 1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE deleted_at IS NULL AND id = ?", sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = sb.Select("*").From("users").WithDeleted().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users", sql)
+
+	sql, _, err = sb.Select("*").From("users").OnlyDeleted().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE deleted_at IS NOT NULL", sql)
+
+	sql, _, err = sb.Select("*").From("items").ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items", sql)
+}
+
+func TestSoftDeleteSelectJoins(t *testing.T) {
+	t.Parallel()
+	sb := StatementBuilder.SoftDelete("users", "deleted_at").SoftDelete("orders", "deleted_at")
+
+	sql, args, err := sb.Select("u.id", "o.id").
+		From("users u").
+		LeftJoin("orders AS o ON o.user_id = u.id OR o.owner_id = ?", 5).
+		Join("items i ON i.order_id = o.id").
+		Where("u.active = ?", true).
+		ToSql()
+	require.NoError(t, err)
+
+	expectedSql := "SELECT u.id, o.id FROM users u " +
+		"LEFT JOIN orders AS o ON (o.user_id = u.id OR o.owner_id = ?) AND o.deleted_at IS NULL " +
+		"JOIN items i ON i.order_id = o.id " +
+		"WHERE u.deleted_at IS NULL AND u.active = ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []any{5, true}, args)
+
+	sql, _, err = sb.Select("*").From("items").CrossJoin("users").ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items CROSS JOIN users WHERE users.deleted_at IS NULL", sql)
+
+	_, _, err = sb.Select("*").From("items").LeftJoin("users USING (id)").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSoftDeleteUpdate(t *testing.T) {
+	t.Parallel()
+	sb := StatementBuilder.SoftDelete("users", "deleted_at")
+
+	sql, _, err := sb.Update("users").Set("name", "moe").Where("id = ?", 1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE deleted_at IS NULL AND id = ?", sql)
+
+	sql, _, err = sb.Update("users").Set("deleted_at", nil).Where("id = ?", 1).OnlyDeleted().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET deleted_at = ? WHERE deleted_at IS NOT NULL AND id = ?", sql)
+
+	sql, _, err = sb.Update("users").Set("name", "moe").WithDeleted().AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?", sql)
+
+	sql, _, err = sb.Update("accounts a").Set("active", false).
+		From("users u").Where("u.id = a.user_id").PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE accounts a SET active = $1 FROM users u WHERE u.deleted_at IS NULL AND u.id = a.user_id", sql)
+}
+
+func TestSoftDeleteDelete(t *testing.T) {
+	t.Parallel()
+	sb := StatementBuilder.SoftDelete("users", "deleted_at")
+
+	sql, args, err := sb.Delete("users").Where("id = ?", 1).Returning("id").PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET deleted_at = now() WHERE deleted_at IS NULL AND id = $1 RETURNING id", sql)
+	assert.Equal(t, []any{1}, args)
+
+	sql, _, err = sb.Delete("users").Where("id = ?", 1).OrderBy("id").Limit(10).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE deleted_at IS NULL AND id = ? "+
+		"ORDER BY id LIMIT 10", sql)
+
+	sql, _, err = sb.Delete("users").Where("id = ?", 1).HardDelete().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ?", sql)
+
+	_, _, err = sb.Delete("users").Using("orders").Where("orders.user_id = users.id").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSoftDeleteInsert(t *testing.T) {
+	t.Parallel()
+	sql, _, err := StatementBuilder.SoftDelete("users", "deleted_at").Insert("users").Values(1).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users VALUES (?)", sql)
+}
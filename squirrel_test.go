@@ -8,7 +8,7 @@ import (
 )
 
 var (
-	testDebugUpdateSQL    = Update("table").SetMap(Eq{"x": 1, "y": "val"})
+	testDebugUpdateSQL    = Update("table").SetMap(Eq{"x": 1, "y": "val"}).AllowFullTable()
 	expectedDebugUpateSQL = "UPDATE table SET x = '1', y = 'val'"
 )
 
@@ -12,13 +12,13 @@ func (b StatementBuilderType) Select(columns ...string) SelectBuilder {
 
 // Insert returns a InsertBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Insert(into string) InsertBuilder {
-	return InsertBuilder(b).Into(into)
+	return InsertBuilder(b.without(filterOnlyFields...)).Into(into)
 }
 
 // Replace returns a InsertBuilder for this StatementBuilderType with the
 // statement keyword set to "REPLACE".
 func (b StatementBuilderType) Replace(into string) InsertBuilder {
-	return InsertBuilder(b).statementKeyword("REPLACE").Into(into)
+	return InsertBuilder(b.without(filterOnlyFields...)).statementKeyword("REPLACE").Into(into)
 }
 
 // Update returns a UpdateBuilder for this StatementBuilderType.
@@ -31,9 +31,20 @@ func (b StatementBuilderType) Delete(from string) DeleteBuilder {
 	return DeleteBuilder(b).From(from)
 }
 
+// Merge returns a MergeBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Merge(into string) MergeBuilder {
+	return MergeBuilder(b.without(filterOnlyFields...)).Into(into)
+}
+
 // With returns a CommonTableExpressionsBuilder for this StatementBuilderType.
-func (b StatementBuilderType) With(cte string) CommonTableExpressionsBuilder {
-	return CommonTableExpressionsBuilder(b).Cte(cte)
+func (b StatementBuilderType) With(cte string, columns ...string) CommonTableExpressionsBuilder {
+	return CommonTableExpressionsBuilder(b.without(filterOnlyFields...)).Cte(cte, columns...)
+}
+
+// WithRecursiveCte returns a CommonTableExpressionsBuilder with the given first recursive cte
+// for this StatementBuilderType.
+func (b StatementBuilderType) WithRecursiveCte(cte RecursiveCteBuilder) CommonTableExpressionsBuilder {
+	return CommonTableExpressionsBuilder(b.without(filterOnlyFields...)).RecursiveCte(cte)
 }
 
 // PlaceholderFormat sets the PlaceholderFormat field for any child builders.
@@ -41,6 +52,34 @@ func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBu
 	return builder.Set(b, "PlaceholderFormat", f).(StatementBuilderType)
 }
 
+// Dialect sets the SQL dialect for any child builders.
+func (b StatementBuilderType) Dialect(d Dialect) StatementBuilderType {
+	return builder.Set(b, "Dialect", d).(StatementBuilderType)
+}
+
+// BindLimitOffset makes LIMIT, OFFSET and FETCH FIRST values of child builders
+// to be passed as args.
+//
+// See SelectBuilder.BindLimitOffset for more information.
+func (b StatementBuilderType) BindLimitOffset(bind bool) StatementBuilderType {
+	return builder.Set(b, "BindLimitOffset", bind).(StatementBuilderType)
+}
+
+// filterOnlyFields are StatementBuilderType fields that are supported only by
+// the SELECT, UPDATE and DELETE builders.
+//
+//nolint:gochecknoglobals // read-only list of field names
+var filterOnlyFields = []string{"BindLimitOffset", "SoftDeletes"}
+
+// without returns a copy of the builder without the given fields, so it can be
+// converted to a builder whose data struct has no such fields.
+func (b StatementBuilderType) without(fields ...string) builder.Builder {
+	for _, field := range fields {
+		b = builder.Delete(b, field).(StatementBuilderType)
+	}
+	return builder.Builder(b)
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -89,18 +128,32 @@ func Delete(from string) DeleteBuilder {
 	return StatementBuilder.Delete(from)
 }
 
-// With returns a new CommonTableExpressionsBuilder with the given first cte name.
+// Merge returns a new MergeBuilder with the given target table name.
+//
+// See MergeBuilder.Into.
+func Merge(into string) MergeBuilder {
+	return StatementBuilder.Merge(into)
+}
+
+// With returns a new CommonTableExpressionsBuilder with the given first cte name and optional columns.
 //
 // See CommonTableExpressionsBuilder.Cte.
-func With(cte string) CommonTableExpressionsBuilder {
-	return StatementBuilder.With(cte)
+func With(cte string, columns ...string) CommonTableExpressionsBuilder {
+	return StatementBuilder.With(cte, columns...)
 }
 
 // WithRecursive returns a new CommonTableExpressionsBuilder with the RECURSIVE option and the given first cte name.
 //
 // See CommonTableExpressionsBuilder.Cte, CommonTableExpressionsBuilder.Recursive.
-func WithRecursive(cte string) CommonTableExpressionsBuilder {
-	return StatementBuilder.With(cte).Recursive(true)
+func WithRecursive(cte string, columns ...string) CommonTableExpressionsBuilder {
+	return StatementBuilder.With(cte, columns...).Recursive(true)
+}
+
+// WithRecursiveCte returns a new CommonTableExpressionsBuilder with the given first recursive cte.
+//
+// See RecursiveCte.
+func WithRecursiveCte(cte RecursiveCteBuilder) CommonTableExpressionsBuilder {
+	return StatementBuilder.WithRecursiveCte(cte)
 }
 
 // Case returns a new CaseBuilder.
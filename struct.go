@@ -0,0 +1,317 @@
+package squirrel
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lann/builder"
+)
+
+// Struct fields are mapped to columns by the "db" tag, the same convention scany and sqlx use:
+//
+//	type User struct {
+//		ID        int64     `db:"id,pk"`
+//		Name      string    `db:"name"`
+//		Nickname  string    `db:"nickname,omitempty"`
+//		CreatedAt time.Time `db:"created_at,generated"`
+//		Password  string    `db:"-"`
+//		Audit               // fields of embedded structs are mapped as fields of User
+//	}
+//
+// Fields without the tag are mapped to snake_case of the field name, unexported fields are ignored.
+// Tag options:
+//   - pk: the field is a primary key column, see StructOption.ExcludePK
+//   - generated: the column value is generated by the database, see StructOption.ExcludeGenerated
+//   - omitempty: the field is skipped if it has zero value
+
+const structTagName = "db"
+
+// StructOption is used to specify how struct fields are mapped to columns.
+type StructOption struct {
+	// SkipZero skips all fields with zero values, as if they were tagged with omitempty.
+	SkipZero bool
+	// ExcludePK skips primary key fields.
+	ExcludePK bool
+	// ExcludeGenerated skips fields of generated columns.
+	ExcludeGenerated bool
+	// Exclude skips the given columns.
+	Exclude []string
+}
+
+// mergeStructOptions merges the given options into one.
+func mergeStructOptions(opts []StructOption) StructOption {
+	var res StructOption
+	for _, opt := range opts {
+		res.SkipZero = res.SkipZero || opt.SkipZero
+		res.ExcludePK = res.ExcludePK || opt.ExcludePK
+		res.ExcludeGenerated = res.ExcludeGenerated || opt.ExcludeGenerated
+		res.Exclude = append(res.Exclude, opt.Exclude...)
+	}
+	return res
+}
+
+// structField is a struct field mapped to a column.
+type structField struct {
+	column    string
+	index     []int
+	pk        bool
+	generated bool
+	omitEmpty bool
+}
+
+// excluded returns true if the field must be skipped regardless of its value.
+func (f structField) excluded(opt StructOption) bool {
+	return (opt.ExcludePK && f.pk) ||
+		(opt.ExcludeGenerated && f.generated) ||
+		slices.Contains(opt.Exclude, f.column)
+}
+
+// omittable returns true if the field must be skipped when it has zero value.
+func (f structField) omittable(opt StructOption) bool {
+	return opt.SkipZero || f.omitEmpty
+}
+
+// toSnakeCase converts field name to a column name, e.g. "UserID" to "user_id".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				_, _ = sb.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		_, _ = sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// isFlattenedStruct returns true if fields of the embedded struct type are mapped as fields of the parent.
+func isFlattenedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	return !t.Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem()) &&
+		!reflect.PointerTo(t).Implements(reflect.TypeOf((*driver.Valuer)(nil)).Elem())
+}
+
+// structFields returns the fields of the struct type mapped to columns.
+func structFields(t reflect.Type, parentIndex []int) []structField {
+	var fields []structField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup(structTagName)
+		if tag == "-" {
+			continue
+		}
+
+		index := append(slices.Clone(parentIndex), i)
+
+		if f.Anonymous && !hasTag && isFlattenedStruct(f.Type) {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			fields = append(fields, structFields(ft, index)...)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		field := structField{
+			column: name,
+			index:  index,
+		}
+		if field.column == "" {
+			field.column = toSnakeCase(f.Name)
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(opt) {
+			case "pk":
+				field.pk = true
+			case "generated":
+				field.generated = true
+			case "omitempty":
+				field.omitEmpty = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// structValue dereferences pointers to the struct value.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("expected struct or pointer to struct, got nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected struct or pointer to struct, got %T", v)
+	}
+	return rv, nil
+}
+
+// fieldValue returns the value of the field, or nil if the field is inside nil embedded pointer.
+func fieldValue(rv reflect.Value, field structField) (val any, isZero bool) {
+	fv, err := rv.FieldByIndexErr(field.index)
+	if err != nil {
+		return nil, true
+	}
+	return fv.Interface(), fv.IsZero()
+}
+
+// structColumnsValues returns columns and values of the struct.
+func structColumnsValues(v any, opts []StructOption) (columns []string, values []any, err error) {
+	opt := mergeStructOptions(opts)
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, field := range structFields(rv.Type(), nil) {
+		if field.excluded(opt) {
+			continue
+		}
+		val, isZero := fieldValue(rv, field)
+		if isZero && field.omittable(opt) {
+			continue
+		}
+		columns = append(columns, field.column)
+		values = append(values, val)
+	}
+	return columns, values, nil
+}
+
+// structRows returns the struct values of the slice elements. All elements must have the same struct type.
+func structRows(slice any) (elems []reflect.Value, elemType reflect.Type, err error) {
+	rv := reflect.ValueOf(slice)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, nil, fmt.Errorf("expected slice of structs, got %T", slice)
+	}
+
+	elemType = rv.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct && elemType.Kind() != reflect.Interface {
+		return nil, nil, fmt.Errorf("expected slice of structs, got %T", slice)
+	}
+
+	elems = make([]reflect.Value, rv.Len())
+	for i := range elems {
+		if elems[i], err = structValue(rv.Index(i).Interface()); err != nil {
+			return nil, nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if elemType.Kind() == reflect.Interface && i == 0 {
+			elemType = elems[i].Type()
+		}
+		if elems[i].Type() != elemType {
+			return nil, nil, fmt.Errorf("element %d: expected %s, got %s", i, elemType, elems[i].Type())
+		}
+	}
+
+	if elemType.Kind() != reflect.Struct {
+		// empty slice of interfaces
+		return nil, nil, fmt.Errorf("expected slice of structs, got %T", slice)
+	}
+	return elems, elemType, nil
+}
+
+// Struct sets columns and values of the insert query from the struct fields.
+// v must be a struct or a pointer to a struct, otherwise ToSql returns an error.
+// Note that it will reset all previous columns and values was set if any.
+//
+// See StructOption for the mapping of struct fields to columns.
+func (b InsertBuilder) Struct(v any, opts ...StructOption) InsertBuilder {
+	columns, values, err := structColumnsValues(v, opts)
+	if err != nil {
+		return builder.Set(b, "Err", err).(InsertBuilder)
+	}
+
+	b = builder.Set(b, "Columns", columns).(InsertBuilder)
+	return builder.Set(b, "Values", [][]any{values}).(InsertBuilder)
+}
+
+// Structs sets columns and values of the multi-row insert query from the slice of structs.
+// All elements must have the same struct type, otherwise ToSql returns an error.
+// Note that it will reset all previous columns and values was set if any.
+//
+// All rows have the same columns, so a field with zero value is skipped
+// (because of omitempty tag or StructOption.SkipZero) only if it is zero in every row.
+func (b InsertBuilder) Structs(slice any, opts ...StructOption) InsertBuilder {
+	elems, elemType, err := structRows(slice)
+	if err != nil {
+		return builder.Set(b, "Err", err).(InsertBuilder)
+	}
+
+	opt := mergeStructOptions(opts)
+
+	var fields []structField
+	for _, field := range structFields(elemType, nil) {
+		if !field.excluded(opt) {
+			fields = append(fields, field)
+		}
+	}
+
+	rows := make([][]any, len(elems))
+	used := make([]bool, len(fields))
+	for i, elem := range elems {
+		rows[i] = make([]any, len(fields))
+		for j, field := range fields {
+			val, isZero := fieldValue(elem, field)
+			rows[i][j] = val
+			used[j] = used[j] || !isZero || !field.omittable(opt)
+		}
+	}
+
+	columns := make([]string, 0, len(fields))
+	for j, field := range fields {
+		if used[j] || len(rows) == 0 {
+			columns = append(columns, field.column)
+		}
+	}
+	for i, row := range rows {
+		values := make([]any, 0, len(columns))
+		for j, val := range row {
+			if used[j] {
+				values = append(values, val)
+			}
+		}
+		rows[i] = values
+	}
+
+	b = builder.Set(b, "Columns", columns).(InsertBuilder)
+	return builder.Set(b, "Values", rows).(InsertBuilder)
+}
+
+// SetStruct adds SET clauses to the query from the struct fields.
+// v must be a struct or a pointer to a struct, otherwise ToSql returns an error.
+//
+// See StructOption for the mapping of struct fields to columns.
+func (b UpdateBuilder) SetStruct(v any, opts ...StructOption) UpdateBuilder {
+	columns, values, err := structColumnsValues(v, opts)
+	if err != nil {
+		return builder.Set(b, "Err", err).(UpdateBuilder)
+	}
+	for i, column := range columns {
+		b = b.Set(column, values[i])
+	}
+	return b
+}
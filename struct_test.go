@@ -0,0 +1,131 @@
+package squirrel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type structTestAudit struct {
+	CreatedBy string    `db:"created_by"`
+	UpdatedAt time.Time `db:"updated_at,generated"`
+}
+
+type structTestUser struct {
+	ID       int64  `db:"id,pk"`
+	Name     string `db:"name"`
+	Nickname string `db:"nickname,omitempty"`
+	UserAge  int
+	Password string `db:"-"`
+	internal string
+	structTestAudit
+}
+
+func TestToSnakeCase(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "name", toSnakeCase("Name"))
+	assert.Equal(t, "user_age", toSnakeCase("UserAge"))
+	assert.Equal(t, "user_id", toSnakeCase("UserID"))
+	assert.Equal(t, "http_server", toSnakeCase("HTTPServer"))
+}
+
+func TestInsertStruct(t *testing.T) {
+	t.Parallel()
+	u := structTestUser{ID: 1, Name: "moe", UserAge: 30, Password: "secret", internal: "x"}
+	u.CreatedBy = "admin"
+
+	sql, args, err := Insert("users").Struct(&u).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,user_age,created_by,updated_at) VALUES (?,?,?,?,?)", sql)
+	assert.Equal(t, []any{int64(1), "moe", 30, "admin", time.Time{}}, args)
+
+	sql, args, err = Insert("users").
+		Struct(u, StructOption{ExcludePK: true, ExcludeGenerated: true}, StructOption{Exclude: []string{"user_age"}}).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,created_by) VALUES (?,?)", sql)
+	assert.Equal(t, []any{"moe", "admin"}, args)
+
+	u.Nickname = "m"
+	sql, _, err = Insert("users").Struct(u, StructOption{SkipZero: true}).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,nickname,user_age,created_by) VALUES (?,?,?,?,?)", sql)
+}
+
+func TestInsertStructs(t *testing.T) {
+	t.Parallel()
+	users := []*structTestUser{
+		{ID: 1, Name: "moe"},
+		{ID: 2, Name: "larry", Nickname: "l"},
+	}
+
+	sql, args, err := Insert("users").Structs(users, StructOption{ExcludeGenerated: true}).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,nickname,user_age,created_by) VALUES (?,?,?,?,?),(?,?,?,?,?)", sql)
+	assert.Equal(t, []any{int64(1), "moe", "", 0, "", int64(2), "larry", "l", 0, ""}, args)
+
+	sql, _, err = Insert("users").Structs(users[:1], StructOption{ExcludeGenerated: true}).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,user_age,created_by) VALUES (?,?,?,?)", sql)
+
+	_, _, err = Insert("users").Structs([]structTestUser{}).ToSql()
+	require.Error(t, err)
+}
+
+func TestStructErrors(t *testing.T) {
+	t.Parallel()
+	var nilUser *structTestUser
+
+	_, _, err := Insert("users").Struct(nilUser).ToSql()
+	require.EqualError(t, err, "expected struct or pointer to struct, got nil *squirrel.structTestUser")
+
+	_, _, err = Insert("users").Struct([]int{1}).ToSql()
+	require.EqualError(t, err, "expected struct or pointer to struct, got []int")
+
+	_, _, err = Update("users").SetStruct(1).Where("id = ?", 1).ToSql()
+	require.EqualError(t, err, "expected struct or pointer to struct, got int")
+
+	_, _, err = Insert("users").Structs(structTestUser{}).ToSql()
+	require.EqualError(t, err, "expected slice of structs, got squirrel.structTestUser")
+
+	_, _, err = Insert("users").Structs([]*structTestUser{{ID: 1}, nil}).ToSql()
+	require.EqualError(t, err, "element 1: expected struct or pointer to struct, got nil *squirrel.structTestUser")
+
+	_, _, err = Insert("users").Structs([]any{structTestUser{ID: 1}, structTestAudit{}}).ToSql()
+	require.EqualError(t, err, "element 1: expected squirrel.structTestUser, got squirrel.structTestAudit")
+
+	sql, args, err := Insert("users").
+		Structs([]any{structTestUser{ID: 1}, &structTestUser{ID: 2}}, StructOption{ExcludeGenerated: true}).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name,user_age,created_by) VALUES (?,?,?,?),(?,?,?,?)", sql)
+	assert.Equal(t, []any{int64(1), "", 0, "", int64(2), "", 0, ""}, args)
+}
+
+func TestUpdateSetStruct(t *testing.T) {
+	t.Parallel()
+	u := structTestUser{ID: 1, Name: "moe", UserAge: 30}
+
+	sql, args, err := Update("users").
+		SetStruct(u, StructOption{ExcludePK: true, ExcludeGenerated: true, SkipZero: true}).
+		Where(Eq{"id": u.ID}).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, user_age = ? WHERE id = ?", sql)
+	assert.Equal(t, []any{"moe", 30, int64(1)}, args)
+}
+
+func TestStructEmbeddedPointer(t *testing.T) {
+	t.Parallel()
+	type row struct {
+		ID int64 `db:"id"`
+		*structTestAudit
+	}
+
+	sql, args, err := Insert("t").Struct(row{ID: 1}).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id,created_by,updated_at) VALUES (?,?,?)", sql)
+	assert.Equal(t, []any{int64(1), nil, nil}, args)
+}
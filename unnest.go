@@ -0,0 +1,142 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// UNNEST mode of multi-row INSERT statements (PostgreSQL only)
+// e.g.
+// INSERT INTO t (a,b) SELECT * FROM unnest(CAST(? AS bigint[]), CAST(? AS text[]))
+// Each column is bound as one array, so the SQL text does not depend on the number of rows.
+
+// unnestColumn builds the array of the column values.
+func unnestColumn(rows [][]any, col int, sqlType string) (array any, arrayType string, err error) {
+	var elemType reflect.Type
+	hasNil := false
+	for _, row := range rows {
+		val := row[col]
+		if _, ok := val.(Sqlizer); ok {
+			return nil, "", errors.New("unnest insert does not support Sqlizer values")
+		}
+		if val == nil {
+			hasNil = true
+			continue
+		}
+		t := reflect.TypeOf(val)
+		if elemType == nil {
+			elemType = t
+		} else if elemType != t {
+			return nil, "", fmt.Errorf("unnest insert column %d has values of different types %s and %s", col, elemType, t)
+		}
+	}
+
+	if sqlType == "" {
+		if elemType == nil {
+			return nil, "", fmt.Errorf("unnest insert column %d type cannot be inferred from nil values", col)
+		}
+		t := elemType
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if sqlType, err = sqlTypeNameHelper(t); err != nil {
+			return nil, "", fmt.Errorf("unnest insert column %d: %w", col, err)
+		}
+	}
+
+	// nil []byte is NULL, so byte slices are not wrapped by pointers
+	bytesType := reflect.TypeOf([]byte(nil))
+	switch {
+	case elemType == nil:
+		elemType = reflect.TypeOf((*any)(nil)).Elem()
+	case hasNil && elemType.Kind() != reflect.Ptr && elemType != bytesType:
+		elemType = reflect.PointerTo(elemType)
+	}
+
+	values := reflect.MakeSlice(reflect.SliceOf(elemType), len(rows), len(rows))
+	for i, row := range rows {
+		if row[col] == nil {
+			continue
+		}
+		val := reflect.ValueOf(row[col])
+		if elemType.Kind() == reflect.Ptr && val.Kind() != reflect.Ptr {
+			ptr := reflect.New(val.Type())
+			ptr.Elem().Set(val)
+			val = ptr
+		}
+		values.Index(i).Set(val)
+	}
+
+	return values.Interface(), sqlType + "[]", nil
+}
+
+func (d *insertData) appendUnnestToSQL(w io.Writer, args []any) ([]any, error) {
+	if len(d.Columns) == 0 {
+		return nil, errors.New("unnest insert must specify columns")
+	}
+	if len(d.UnnestTypes) > 0 && len(d.UnnestTypes) != len(d.Columns) {
+		return nil, fmt.Errorf("unnest insert has %d types for %d columns", len(d.UnnestTypes), len(d.Columns))
+	}
+	for _, row := range d.Values {
+		if len(row) != len(d.Columns) {
+			return nil, fmt.Errorf("unnest insert row has %d values for %d columns", len(row), len(d.Columns))
+		}
+	}
+
+	casts := make([]string, len(d.Columns))
+	for col := range d.Columns {
+		sqlType := ""
+		if len(d.UnnestTypes) > 0 {
+			sqlType = d.UnnestTypes[col]
+		}
+
+		array, arrayType, err := unnestColumn(d.Values, col, sqlType)
+		if err != nil {
+			return nil, err
+		}
+		casts[col] = fmt.Sprintf("CAST(? AS %s)", arrayType)
+		args = append(args, array)
+	}
+
+	_, _ = io.WriteString(w, "SELECT * FROM unnest(")
+	_, _ = io.WriteString(w, strings.Join(casts, ", "))
+	_, _ = io.WriteString(w, ")")
+
+	return args, nil
+}
+
+// checkUnnest checks that the UNNEST mode can be used.
+func (d *insertData) checkUnnest(dialect Dialect) error {
+	if !d.Unnest {
+		return nil
+	}
+	if dialect != DialectPostgres && dialect != DialectUndefined {
+		return errUnsupported("unnest insert", dialect)
+	}
+	if d.Select != nil {
+		return errors.New("unnest insert cannot be used with insert select")
+	}
+	return nil
+}
+
+// Unnest makes the multi-row insert query to bind one array per column instead of
+// one parameter per value (PostgreSQL only):
+//
+//	INSERT INTO t (a,b) SELECT * FROM unnest(CAST(? AS bigint[]), CAST(? AS text[]))
+//
+// The SQL text does not depend on the number of rows, and the statement always has
+// as many bind parameters as columns.
+//
+// Array types are inferred from the Go types of the values the same way as CASE
+// THEN values are. Types can be set explicitly (empty string to infer the type),
+// which is required for columns of unsupported Go types or with nil values only.
+// Values of each column must have the same Go type, Sqlizer values are not supported.
+func (b InsertBuilder) Unnest(types ...string) InsertBuilder {
+	b = builder.Set(b, "UnnestTypes", types).(InsertBuilder)
+	return builder.Set(b, "Unnest", true).(InsertBuilder)
+}
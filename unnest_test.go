@@ -0,0 +1,79 @@
+package squirrel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertUnnest(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("t").Columns("a", "b").
+		Values(1, "x").
+		Values(2, "y").
+		Values(3, "z").
+		Unnest().
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b) SELECT * FROM unnest(CAST($1 AS bigint[]), CAST($2 AS text[]))", sql)
+	assert.Equal(t, []any{[]int{1, 2, 3}, []string{"x", "y", "z"}}, args)
+}
+
+func TestInsertUnnestNulls(t *testing.T) {
+	t.Parallel()
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sql, args, err := Insert("t").Columns("a", "b", "c").
+		Values(int32(1), nil, nil).
+		Values(nil, ts, nil).
+		Unnest("", "", "jsonb").
+		OnConflict().DoNothing().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a,b,c) SELECT * FROM unnest(CAST(? AS integer[]), "+
+		"CAST(? AS timestamp with time zone[]), CAST(? AS jsonb[])) ON CONFLICT DO NOTHING", sql)
+
+	one := int32(1)
+	assert.Equal(t, []any{[]*int32{&one, nil}, []*time.Time{nil, &ts}, []any{nil, nil}}, args)
+}
+
+func TestInsertUnnestBytes(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Insert("t").Columns("a").
+		Values([]byte("x")).
+		Values(nil).
+		Unnest().
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) SELECT * FROM unnest(CAST(? AS bytea[]))", sql)
+	assert.Equal(t, []any{[][]byte{[]byte("x"), nil}}, args)
+}
+
+func TestInsertUnnestErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Insert("t").Columns("a").Values(1).Values(int32(2)).Unnest().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Columns("a").Values(nil).Unnest().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Columns("a").Values(Expr("now()")).Unnest().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Values(1).Unnest().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Columns("a", "b").Values(1).Unnest().ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Columns("a").Values(1).Unnest("bigint", "text").ToSql()
+	require.Error(t, err)
+
+	_, _, err = Insert("t").Columns("a").Values(1).Unnest().Dialect(DialectMySQL).ToSql()
+	require.EqualError(t, err, "unnest insert is not supported by the mysql dialect")
+
+	_, _, err = Insert("t").Columns("a").Select(Select("a").From("s")).Unnest().ToSql()
+	require.Error(t, err)
+}
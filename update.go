@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/lann/builder"
@@ -13,15 +12,27 @@ import (
 
 type updateData struct {
 	PlaceholderFormat PlaceholderFormat
+	Dialect           Dialect
 	Prefixes          []Sqlizer
 	Table             string
 	SetClauses        []setClause
 	From              Sqlizer
+	Joins             []Sqlizer
+	ValuesFrom        *valuesFrom
 	WhereParts        []Sqlizer
 	OrderBys          []string
-	Limit             string
-	Offset            string
+	Limit             *uint64
+	Offset            *uint64
+	BindLimitOffset   bool     // LIMIT and OFFSET values are passed as args.
+	LimitKey          []string // row key columns of the postgres LIMIT emulation, see LimitKey
+	Returning         []any
 	Suffixes          []Sqlizer
+	VersionColumn     string // optimistic locking column, see WithVersion
+	Version           any
+	SoftDeletes       []softDeleteTable
+	SoftDeleteScope   softDeleteScope
+	AllowFullTable    bool  // statement without WHERE conditions is allowed, see ErrFullTable
+	Err               error // error of the builder methods (e.g. SetStruct), returned by ToSql
 }
 
 type setClause struct {
@@ -43,7 +54,15 @@ func (d *updateData) writePrefixes(sql *bytes.Buffer, args []any) ([]any, error)
 	return args, nil
 }
 
-func buildSetClauseSQL(sc setClause) (sql string, args []any, err error) {
+func buildSetClauseSQL(sc setClause, dialect Dialect) (sql string, args []any, err error) {
+	if ds, ok := sc.value.(dialectSqlizer); ok {
+		vsql, vargs, err := ds.toSqlDialect(dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s = %s", sc.column, vsql), vargs, nil
+	}
+
 	vs, ok := sc.value.(Sqlizer)
 	if !ok {
 		return sc.column + " = ?", []any{sc.value}, nil
@@ -61,12 +80,16 @@ func buildSetClauseSQL(sc setClause) (sql string, args []any, err error) {
 	return fmt.Sprintf("%s = %s", sc.column, vsql), vargs, nil
 }
 
-func (d *updateData) writeSetClauses(sql *bytes.Buffer, args []any) ([]any, error) {
+func (d *updateData) writeSetClauses(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
 	_, _ = sql.WriteString(" SET ")
 
-	setSqls := make([]string, len(d.SetClauses))
-	for i, sc := range d.SetClauses {
-		setSql, setArgs, err := buildSetClauseSQL(sc)
+	setClauses, err := mergeSetClauses(d.SetClauses)
+	if err != nil {
+		return nil, err
+	}
+	setSqls := make([]string, len(setClauses))
+	for i, sc := range setClauses {
+		setSql, setArgs, err := buildSetClauseSQL(sc, dialect)
 		if err != nil {
 			return nil, err
 		}
@@ -78,13 +101,48 @@ func (d *updateData) writeSetClauses(sql *bytes.Buffer, args []any) ([]any, erro
 	return args, nil
 }
 
-func (d *updateData) writeFromClause(sql *bytes.Buffer, args []any) ([]any, error) {
-	if d.From == nil {
+// writeMySQLJoins writes joins after the table for MySQL: "UPDATE t JOIN j ON ... SET ...".
+func (d *updateData) writeMySQLJoins(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	if dialect != DialectMySQL || len(d.Joins) == 0 {
+		return args, nil
+	}
+
+	_, _ = sql.WriteString(" ")
+	return appendToSql(d.Joins, sql, " ", args)
+}
+
+// writeFromClause writes FROM clause with joins for dialects other than MySQL.
+func (d *updateData) writeFromClause(sql *bytes.Buffer, dialect Dialect, args []any) ([]any, error) {
+	from := d.From
+	joins := d.Joins
+	if dialect == DialectMySQL {
+		joins = nil
+	}
+
+	if len(joins) > 0 && from == nil {
+		switch dialect { //nolint:exhaustive // other dialects require From
+		case DialectSQLServer:
+			// UPDATE t SET ... FROM t JOIN j ON ...
+			from = newPart(d.Table)
+		case DialectOracle:
+			return nil, errUnsupported("UPDATE with joins", dialect)
+		default:
+			return nil, fmt.Errorf("update joins require From clause for the %s dialect", dialect)
+		}
+	}
+
+	if from == nil {
 		return args, nil
 	}
 
 	_, _ = sql.WriteString(" FROM ")
-	return appendToSql([]Sqlizer{d.From}, sql, "", args)
+	args, err := appendToSql([]Sqlizer{from}, sql, "", args)
+	if err != nil || len(joins) == 0 {
+		return args, err
+	}
+
+	_, _ = sql.WriteString(" ")
+	return appendToSql(joins, sql, " ", args)
 }
 
 func (d *updateData) writeWhereClause(sql *bytes.Buffer, args []any) ([]any, error) {
@@ -103,16 +161,16 @@ func (d *updateData) writeOrderByClause(sql *bytes.Buffer) {
 	}
 }
 
-func (d *updateData) writeLimitOffset(sql *bytes.Buffer) {
-	if d.Limit != "" {
-		_, _ = sql.WriteString(" LIMIT ")
-		_, _ = sql.WriteString(d.Limit)
+func (d *updateData) writeLimitOffset(sql *bytes.Buffer, args []any) []any {
+	if d.Limit != nil {
+		args = writeLimitValue(sql, "LIMIT", *d.Limit, d.BindLimitOffset, args)
 	}
 
-	if d.Offset != "" {
-		_, _ = sql.WriteString(" OFFSET ")
-		_, _ = sql.WriteString(d.Offset)
+	if d.Offset != nil {
+		args = writeLimitValue(sql, "OFFSET", *d.Offset, d.BindLimitOffset, args)
 	}
+
+	return args
 }
 
 func (d *updateData) writeSuffixes(sql *bytes.Buffer, args []any) ([]any, error) {
@@ -125,13 +183,48 @@ func (d *updateData) writeSuffixes(sql *bytes.Buffer, args []any) ([]any, error)
 }
 
 func (d *updateData) toSqlRaw() (sqlStr string, args []any, err error) {
+	if d.Err != nil {
+		return "", nil, d.Err
+	}
 	if d.Table == "" {
 		return "", nil, errors.New("update statements must specify a table")
 	}
+
+	if err = d.checkVersionSet(); err != nil {
+		return "", nil, err
+	}
+
+	dialect := resolveDialect(d.Dialect, d.PlaceholderFormat)
+	if err = checkReturning(dialect, d.Returning); err != nil {
+		return "", nil, err
+	}
+
+	if !d.AllowFullTable && d.ValuesFrom == nil && !d.onlyDeleted() {
+		if err = checkFullTable(dialect, d.WhereParts, d.Joins); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if d, err = d.applySoftDelete(); err != nil {
+		return "", nil, err
+	}
+
+	if d.ValuesFrom != nil {
+		if d, err = d.expandValuesFrom(dialect); err != nil {
+			return "", nil, err
+		}
+	}
+
 	if len(d.SetClauses) == 0 {
 		return "", nil, errors.New("update statements must have at least one Set clause")
 	}
 
+	if needsLimitEmulation(dialect, d.OrderBys, d.Limit, d.Offset) {
+		if d, err = d.emulateLimit(); err != nil {
+			return "", nil, err
+		}
+	}
+
 	sql := &bytes.Buffer{}
 
 	if args, err = d.writePrefixes(sql, args); err != nil {
@@ -139,13 +232,28 @@ func (d *updateData) toSqlRaw() (sqlStr string, args []any, err error) {
 	}
 
 	_, _ = sql.WriteString("UPDATE ")
-	_, _ = sql.WriteString(d.Table)
+	if dialect == DialectSQLServer && len(d.Joins) > 0 && d.From == nil {
+		// UPDATE alias SET ... FROM table alias JOIN ...
+		_, _ = sql.WriteString(tableRef(d.Table))
+	} else {
+		_, _ = sql.WriteString(d.Table)
+	}
 
-	if args, err = d.writeSetClauses(sql, args); err != nil {
+	if args, err = d.writeMySQLJoins(sql, dialect, args); err != nil {
 		return "", nil, err
 	}
 
-	if args, err = d.writeFromClause(sql, args); err != nil {
+	if args, err = d.writeSetClauses(sql, dialect, args); err != nil {
+		return "", nil, err
+	}
+
+	if dialect == DialectSQLServer {
+		if args, err = writeReturning(sql, d.Returning, dialect, outputInserted, args); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if args, err = d.writeFromClause(sql, dialect, args); err != nil {
 		return "", nil, err
 	}
 
@@ -153,8 +261,14 @@ func (d *updateData) toSqlRaw() (sqlStr string, args []any, err error) {
 		return "", nil, err
 	}
 
+	if dialect != DialectSQLServer {
+		if args, err = writeReturning(sql, d.Returning, dialect, outputInserted, args); err != nil {
+			return "", nil, err
+		}
+	}
+
 	d.writeOrderByClause(sql)
-	d.writeLimitOffset(sql)
+	args = d.writeLimitOffset(sql, args)
 
 	if args, err = d.writeSuffixes(sql, args); err != nil {
 		return "", nil, err
@@ -189,6 +303,12 @@ func (b UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) UpdateBuilder {
 	return builder.Set(b, "PlaceholderFormat", f).(UpdateBuilder)
 }
 
+// Dialect sets the SQL dialect of the query. If not set, the dialect is
+// detected by the PlaceholderFormat.
+func (b UpdateBuilder) Dialect(d Dialect) UpdateBuilder {
+	return builder.Set(b, "Dialect", d).(UpdateBuilder)
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
@@ -254,6 +374,31 @@ func (b UpdateBuilder) FromSelect(from SelectBuilder, alias string) UpdateBuilde
 	return builder.Set(b, "From", Alias(from, alias)).(UpdateBuilder)
 }
 
+// JoinClause adds a join clause to the query.
+//
+// For MySQL it renders "UPDATE t JOIN j ON ... SET ...". For SQL Server the joins are
+// added to FROM clause, which defaults to the updated table: "UPDATE a SET ... FROM t a JOIN j ON ...".
+// PostgreSQL and SQLite do not support joins to the updated table, so the joins are added
+// to the From table: "UPDATE t SET ... FROM f JOIN j ON ... WHERE t.id = f.id".
+func (b UpdateBuilder) JoinClause(pred any, args ...any) UpdateBuilder {
+	return builder.Append(b, "Joins", newJoinPart(pred, args...)).(UpdateBuilder)
+}
+
+// Join adds a JOIN clause to the query.
+func (b UpdateBuilder) Join(join string, rest ...any) UpdateBuilder {
+	return b.JoinClause("JOIN "+join, rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b UpdateBuilder) LeftJoin(join string, rest ...any) UpdateBuilder {
+	return b.JoinClause("LEFT JOIN "+join, rest...)
+}
+
+// InnerJoin adds a INNER JOIN clause to the query.
+func (b UpdateBuilder) InnerJoin(join string, rest ...any) UpdateBuilder {
+	return b.JoinClause("INNER JOIN "+join, rest...)
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -268,12 +413,29 @@ func (b UpdateBuilder) OrderBy(orderBys ...string) UpdateBuilder {
 
 // Limit sets a LIMIT clause on the query.
 func (b UpdateBuilder) Limit(limit uint64) UpdateBuilder {
-	return builder.Set(b, "Limit", strconv.FormatUint(limit, 10)).(UpdateBuilder)
+	return builder.Set(b, "Limit", &limit).(UpdateBuilder)
 }
 
 // Offset sets a OFFSET clause on the query.
 func (b UpdateBuilder) Offset(offset uint64) UpdateBuilder {
-	return builder.Set(b, "Offset", strconv.FormatUint(offset, 10)).(UpdateBuilder)
+	return builder.Set(b, "Offset", &offset).(UpdateBuilder)
+}
+
+// BindLimitOffset makes LIMIT and OFFSET values to be passed as args.
+//
+// See SelectBuilder.BindLimitOffset for more information.
+func (b UpdateBuilder) BindLimitOffset(bind bool) UpdateBuilder {
+	return builder.Set(b, "BindLimitOffset", bind).(UpdateBuilder)
+}
+
+// Returning adds RETURNING clause columns to the query. Columns can be strings
+// or Sqlizers, e.g. "id", Old("name") or New("name").
+//
+// For SQL Server OUTPUT clause is rendered after SET clause, and plain column
+// names are qualified with "inserted". RETURNING is not supported by MySQL and
+// Oracle dialects.
+func (b UpdateBuilder) Returning(columns ...any) UpdateBuilder {
+	return builder.Extend(b, "Returning", columns).(UpdateBuilder)
 }
 
 // Suffix adds an expression to the end of the query.
@@ -61,7 +61,7 @@ func TestUpdateBuilderMustSql(t *testing.T) {
 
 func TestUpdateBuilderPlaceholders(t *testing.T) {
 	t.Parallel()
-	b := Update("test").SetMap(Eq{"x": 1, "y": 2})
+	b := Update("test").SetMap(Eq{"x": 1, "y": 2}).AllowFullTable()
 
 	sql, _, _ := b.PlaceholderFormat(Question).ToSql()
 	assert.Equal(t, "UPDATE test SET x = ?, y = ?", sql)
@@ -111,3 +111,38 @@ func TestUpdateSetWithNestedSelect_DollarPlaceholderNumberingConflict(t *testing
 	assert.Equal(t, expectedSQL, sql)
 	assert.Equal(t, []any{11, 12}, args)
 }
+
+func TestUpdateBuilderJoin(t *testing.T) {
+	t.Parallel()
+	b := Update("orders o").
+		Join("customers c ON c.id = o.customer_id").
+		Set("o.status", "blocked").
+		Where("c.blocked = ?", true)
+
+	sql, args, err := b.Dialect(DialectMySQL).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE orders o JOIN customers c ON c.id = o.customer_id SET o.status = ? WHERE c.blocked = ?", sql)
+	assert.Equal(t, []any{"blocked", true}, args)
+
+	sql, _, err = b.PlaceholderFormat(AtP).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE o SET o.status = @p1 FROM orders o JOIN customers c ON c.id = o.customer_id "+
+		"WHERE c.blocked = @p2", sql)
+
+	sql, _, err = Update("orders").
+		Set("status", "blocked").
+		From("customers c").
+		LeftJoin("regions r ON r.id = c.region_id").
+		Where("orders.customer_id = c.id AND r.id IS NULL").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET status = $1 FROM customers c LEFT JOIN regions r ON r.id = c.region_id "+
+		"WHERE orders.customer_id = c.id AND r.id IS NULL", sql)
+
+	_, _, err = b.PlaceholderFormat(Dollar).ToSql()
+	require.EqualError(t, err, "update joins require From clause for the postgres dialect")
+
+	_, _, err = b.Dialect(DialectOracle).ToSql()
+	require.Error(t, err)
+}
@@ -0,0 +1,291 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/lann/builder"
+)
+
+// VALUES list helper
+// e.g.
+// (VALUES (?, ?), (?, ?)) AS t(id, qty)
+//
+// It can be used as a table source in FROM and JOIN clauses, as the source of
+// INSERT ... SELECT and as the body of a CTE.
+
+type valuesData struct {
+	PlaceholderFormat PlaceholderFormat
+	Rows              [][]any
+	Alias             string
+	Columns           []string
+	Types             []string
+	InferTypes        bool
+}
+
+func (d *valuesData) validate() error {
+	if len(d.Rows) == 0 {
+		return errors.New("values lists must have at least one row")
+	}
+
+	width := len(d.Rows[0])
+	if width == 0 {
+		return errors.New("values list rows must have at least one value")
+	}
+	if len(d.Columns) > 0 && len(d.Columns) != width {
+		return fmt.Errorf("values list has %d columns, but rows have %d values", len(d.Columns), width)
+	}
+	if len(d.Types) > 0 && len(d.Types) != width {
+		return fmt.Errorf("values list has %d types, but rows have %d values", len(d.Types), width)
+	}
+
+	for i, row := range d.Rows {
+		if len(row) != width {
+			return fmt.Errorf("values list row %d has %d values, expected %d", i, len(row), width)
+		}
+	}
+
+	return nil
+}
+
+// columnTypes returns SQL types used to cast the first row.
+// An empty string means that the column is not casted.
+func (d *valuesData) columnTypes() []string {
+	if len(d.Types) > 0 {
+		return d.Types
+	}
+	if !d.InferTypes {
+		return nil
+	}
+
+	types := make([]string, len(d.Rows[0]))
+	for i := range types {
+		for _, row := range d.Rows {
+			if t, ok := inferValueType(row[i]); ok {
+				types[i] = t
+				break
+			}
+		}
+	}
+
+	return types
+}
+
+// inferValueType returns SQL type name of the Go value.
+// Returns false if the value is nil, a Sqlizer or its type can't be mapped.
+func inferValueType(val any) (string, bool) {
+	if val == nil {
+		return "", false
+	}
+	if _, ok := val.(Sqlizer); ok {
+		return "", false
+	}
+	if _, ok := val.(driver.Valuer); ok {
+		return "", false
+	}
+
+	t := reflect.TypeOf(val)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	sqlType, err := sqlTypeNameHelper(t)
+	if err != nil {
+		return "", false
+	}
+	return sqlType, true
+}
+
+func (d *valuesData) writeRow(sql *bytes.Buffer, row []any, types []string, args []any) ([]any, error) {
+	_, _ = sql.WriteString("(")
+
+	for i, val := range row {
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+		}
+
+		valSql := "?"
+		if vs, ok := val.(Sqlizer); ok {
+			vsql, vargs, err := nestedToSql(vs)
+			if err != nil {
+				return nil, err
+			}
+			valSql = vsql
+			args = append(args, vargs...)
+		} else {
+			args = append(args, val)
+		}
+
+		if i < len(types) && types[i] != "" {
+			valSql = fmt.Sprintf("CAST(%s AS %s)", valSql, types[i])
+		}
+		_, _ = sql.WriteString(valSql)
+	}
+
+	_, _ = sql.WriteString(")")
+	return args, nil
+}
+
+// writeValues writes "VALUES (...), (...)" without alias.
+func (d *valuesData) writeValues(sql *bytes.Buffer, args []any) ([]any, error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+
+	_, _ = sql.WriteString("VALUES ")
+
+	types := d.columnTypes()
+	var err error
+	for i, row := range d.Rows {
+		if i > 0 {
+			_, _ = sql.WriteString(", ")
+			types = nil // only the first row is casted
+		}
+
+		if args, err = d.writeRow(sql, row, types, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+func (d *valuesData) writeAlias(sql *bytes.Buffer) {
+	_, _ = sql.WriteString(" AS ")
+	_, _ = sql.WriteString(d.Alias)
+
+	if len(d.Columns) > 0 {
+		_, _ = sql.WriteString("(")
+		_, _ = sql.WriteString(strings.Join(d.Columns, ", "))
+		_, _ = sql.WriteString(")")
+	}
+}
+
+func (d *valuesData) toSqlRaw() (sqlStr string, args []any, err error) {
+	sql := &bytes.Buffer{}
+
+	if d.Alias == "" {
+		if args, err = d.writeValues(sql, args); err != nil {
+			return "", nil, err
+		}
+		return sql.String(), args, nil
+	}
+
+	_, _ = sql.WriteString("(")
+	if args, err = d.writeValues(sql, args); err != nil {
+		return "", nil, err
+	}
+	_, _ = sql.WriteString(")")
+
+	d.writeAlias(sql)
+
+	return sql.String(), args, nil
+}
+
+func (d *valuesData) ToSql() (sqlStr string, args []any, err error) {
+	s, a, e := d.toSqlRaw()
+	if e != nil {
+		return "", nil, e
+	}
+	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(s)
+	return sqlStr, a, err
+}
+
+// Builder
+
+// ValuesBuilder builds SQL VALUES lists.
+type ValuesBuilder builder.Builder
+
+func init() { //nolint:gochecknoinits // required to register ValuesBuilder
+	builder.Register(ValuesBuilder{}, valuesData{}) //nolint:exhaustruct // empty struct is fine
+}
+
+// Values returns a new ValuesBuilder with the given rows.
+//
+// Ex:
+//
+//	Values([][]any{{1, 10}, {2, 20}}).As("t", "id", "qty")
+//	// (VALUES (?, ?), (?, ?)) AS t(id, qty)
+func Values(rows [][]any) ValuesBuilder {
+	return ValuesBuilder(builder.EmptyBuilder).PlaceholderFormat(Question).Rows(rows...)
+}
+
+// Format methods
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b ValuesBuilder) PlaceholderFormat(f PlaceholderFormat) ValuesBuilder {
+	return builder.Set(b, "PlaceholderFormat", f).(ValuesBuilder)
+}
+
+// SQL methods
+
+// ToSql builds the query into a SQL string and bound args.
+func (b ValuesBuilder) ToSql() (sql string, args []any, err error) {
+	data := builder.GetStruct(b).(valuesData)
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b ValuesBuilder) MustSql() (sql string, args []any) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// toSqlRaw builds SQL with raw placeholders ("?") without applying PlaceholderFormat.
+func (b ValuesBuilder) toSqlRaw() (sql string, args []any, err error) {
+	data := builder.GetStruct(b).(valuesData)
+	return data.toSqlRaw()
+}
+
+// Rows adds rows to the VALUES list.
+func (b ValuesBuilder) Rows(rows ...[]any) ValuesBuilder {
+	return builder.Extend(b, "Rows", rows).(ValuesBuilder)
+}
+
+// Row adds a single row to the VALUES list.
+func (b ValuesBuilder) Row(values ...any) ValuesBuilder {
+	return builder.Append(b, "Rows", values).(ValuesBuilder)
+}
+
+// As sets the table alias and optional column names of the VALUES list.
+// With alias the list is rendered as "(VALUES ...) AS alias(columns)".
+func (b ValuesBuilder) As(alias string, columns ...string) ValuesBuilder {
+	b = builder.Set(b, "Alias", alias).(ValuesBuilder)
+	return builder.Set(b, "Columns", columns).(ValuesBuilder)
+}
+
+// Types sets SQL types for the columns. Values of the first row are wrapped
+// in CAST(? AS type), which allows PostgreSQL to infer the column types of the
+// whole list. Empty type leaves the column as is.
+func (b ValuesBuilder) Types(types ...string) ValuesBuilder {
+	return builder.Set(b, "Types", types).(ValuesBuilder)
+}
+
+// InferTypes casts the first row like Types does, but takes the SQL types
+// from Go types of the values (see Case for the mapping).
+// Columns with nil, Sqlizer or unsupported values are left without cast.
+func (b ValuesBuilder) InferTypes() ValuesBuilder {
+	return builder.Set(b, "InferTypes", true).(ValuesBuilder)
+}
+
+// valuesTable is a VALUES list used as a table source, which requires an alias.
+type valuesTable struct {
+	values ValuesBuilder
+}
+
+func (t valuesTable) ToSql() (sql string, args []any, err error) {
+	data := builder.GetStruct(t.values).(valuesData)
+	if data.Alias == "" {
+		return "", nil, errors.New("values list used as a table source must have an alias")
+	}
+	return data.toSqlRaw()
+}
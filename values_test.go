@@ -0,0 +1,96 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValuesBuilderToSql(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Values([][]any{{1, 10}, {2, Expr("? + 1", 20)}}).ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "VALUES (?, ?), (?, ? + 1)", sql)
+	assert.Equal(t, []any{1, 10, 2, 20}, args)
+
+	sql, _, err = Values([][]any{{1, 10}}).Row(2, 20).As("t", "id", "qty").PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "(VALUES ($1, $2), ($3, $4)) AS t(id, qty)", sql)
+}
+
+func TestValuesBuilderTypes(t *testing.T) {
+	t.Parallel()
+	sql, args, err := Values([][]any{{1, "a"}, {2, "b"}}).As("t", "id", "name").Types("bigint", "").ToSql()
+	require.NoError(t, err)
+
+	assert.Equal(t, "(VALUES (CAST(? AS bigint), ?), (?, ?)) AS t(id, name)", sql)
+	assert.Equal(t, []any{1, "a", 2, "b"}, args)
+
+	sql, _, err = Values([][]any{{nil, "a", Expr("now()")}, {int32(2), "b", nil}}).InferTypes().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "VALUES (CAST(? AS integer), CAST(? AS text), now()), (?, ?, ?)", sql)
+}
+
+func TestValuesBuilderToSqlErr(t *testing.T) {
+	t.Parallel()
+	_, _, err := Values(nil).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Values([][]any{{1, 2}, {3}}).ToSql()
+	require.Error(t, err)
+
+	_, _, err = Values([][]any{{1, 2}}).As("t", "id").ToSql()
+	require.Error(t, err)
+
+	_, _, err = Values([][]any{{1, 2}}).Types("bigint").ToSql()
+	require.Error(t, err)
+
+	_, _, err = Select("*").FromValues(Values([][]any{{1}})).ToSql()
+	require.Error(t, err)
+}
+
+func TestValuesBuilderAsTableSource(t *testing.T) {
+	t.Parallel()
+	v := Values([][]any{{1, 10}, {2, 20}}).As("v", "id", "qty")
+
+	sql, args, err := Select("v.id", "v.qty").FromValues(v).Where("v.qty > ?", 5).PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT v.id, v.qty FROM (VALUES ($1, $2), ($3, $4)) AS v(id, qty) WHERE v.qty > $5", sql)
+	assert.Equal(t, []any{1, 10, 2, 20, 5}, args)
+
+	sql, args, err = Select("p.name", "v.qty").
+		From("products p").
+		Join("? ON v.id = p.id", v).
+		Where("p.active = ?", true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT p.name, v.qty FROM products p "+
+		"JOIN (VALUES ($1, $2), ($3, $4)) AS v(id, qty) ON v.id = p.id WHERE p.active = $5", sql)
+	assert.Equal(t, []any{1, 10, 2, 20, true}, args)
+}
+
+func TestValuesBuilderInInsertAndCte(t *testing.T) {
+	t.Parallel()
+	v := Values([][]any{{1, 10}, {2, 20}}).As("v", "id", "qty").InferTypes()
+
+	sql, args, err := Insert("stock").
+		Columns("id", "qty").
+		Select(Select("*").FromValues(v)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO stock (id,qty) "+
+		"SELECT * FROM (VALUES (CAST($1 AS bigint), CAST($2 AS bigint)), ($3, $4)) AS v(id, qty)", sql)
+	assert.Equal(t, []any{1, 10, 2, 20}, args)
+
+	sql, _, err = With("input").As(Select("*").FromValues(v)).
+		Select(Select("id").From("input").Where("qty > ?", 0)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "WITH input AS (SELECT * FROM (VALUES (CAST($1 AS bigint), CAST($2 AS bigint)), ($3, $4)) "+
+		"AS v(id, qty)) SELECT id FROM input WHERE qty > $5", sql)
+}
@@ -0,0 +1,95 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lann/builder"
+)
+
+// ErrStaleVersion is returned by CheckVersion if no rows were affected by the query
+// with the version condition, i.e. the row was changed or deleted concurrently.
+var ErrStaleVersion = errors.New("stale version")
+
+// StaleVersionError describes the row which version check failed.
+// It matches ErrStaleVersion with errors.Is.
+type StaleVersionError struct {
+	Table    string
+	Column   string
+	Expected any
+}
+
+// Error implements error interface.
+func (e *StaleVersionError) Error() string {
+	return fmt.Sprintf("%s: %s.%s is not %v", ErrStaleVersion, e.Table, e.Column, e.Expected)
+}
+
+// Is makes the error to match ErrStaleVersion.
+func (e *StaleVersionError) Is(target error) bool {
+	return target == ErrStaleVersion //nolint:errorlint // sentinel comparison
+}
+
+// checkVersion returns StaleVersionError if the version is checked and no rows were affected.
+func checkVersion(table, column string, expected any, rowsAffected int64) error {
+	if column == "" || rowsAffected > 0 {
+		return nil
+	}
+	return &StaleVersionError{Table: table, Column: column, Expected: expected}
+}
+
+// versionCond is the version condition of WithVersion. It is not a filter of the rows,
+// so it is skipped by the full table check (see ErrFullTable).
+type versionCond struct {
+	Eq
+}
+
+// WithVersion implements optimistic locking: it adds "column = column + 1" SET clause
+// and "column = expected" WHERE condition to the query. The column cannot be set by other
+// SET clauses (e.g. SetStruct), ToSql returns an error.
+// Pass the result of the query execution to CheckVersion to detect the stale version.
+func (b UpdateBuilder) WithVersion(column string, expected any) UpdateBuilder {
+	b = builder.Set(b, "VersionColumn", column).(UpdateBuilder)
+	b = builder.Set(b, "Version", expected).(UpdateBuilder)
+	return b.Set(column, Expr(column+" + 1")).Where(versionCond{Eq{column: expected}})
+}
+
+// checkVersionSet returns an error if the version column of WithVersion is also set explicitly,
+// e.g. by SetStruct or SetMap, because it is incremented by the query.
+func (d *updateData) checkVersionSet() error {
+	if d.VersionColumn == "" {
+		return nil
+	}
+
+	n := 0
+	for _, sc := range d.SetClauses {
+		if sc.column == d.VersionColumn {
+			n++
+		}
+	}
+	if n > 1 {
+		return fmt.Errorf("version column %s is incremented by WithVersion and cannot be set", d.VersionColumn)
+	}
+	return nil
+}
+
+// CheckVersion returns StaleVersionError (matching ErrStaleVersion) if the query
+// has the version condition set by WithVersion and no rows were affected.
+func (b UpdateBuilder) CheckVersion(rowsAffected int64) error {
+	data := builder.GetStruct(b).(updateData)
+	return checkVersion(data.Table, data.VersionColumn, data.Version, rowsAffected)
+}
+
+// WithVersion implements optimistic locking: it adds "column = expected" WHERE condition to the query.
+// Pass the result of the query execution to CheckVersion to detect the stale version.
+func (b DeleteBuilder) WithVersion(column string, expected any) DeleteBuilder {
+	b = builder.Set(b, "VersionColumn", column).(DeleteBuilder)
+	b = builder.Set(b, "Version", expected).(DeleteBuilder)
+	return b.Where(versionCond{Eq{column: expected}})
+}
+
+// CheckVersion returns StaleVersionError (matching ErrStaleVersion) if the query
+// has the version condition set by WithVersion and no rows were affected.
+func (b DeleteBuilder) CheckVersion(rowsAffected int64) error {
+	data := builder.GetStruct(b).(deleteData)
+	return checkVersion(data.From, data.VersionColumn, data.Version, rowsAffected)
+}
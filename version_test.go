@@ -0,0 +1,70 @@
+package squirrel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateWithVersion(t *testing.T) {
+	t.Parallel()
+	b := Update("users").Set("name", "moe").Where("id = ?", 1).WithVersion("version", 3)
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = $1, version = version + 1 WHERE id = $2 AND version = $3", sql)
+	assert.Equal(t, []any{"moe", 1, 3}, args)
+
+	require.NoError(t, b.CheckVersion(1))
+
+	err = b.CheckVersion(0)
+	require.ErrorIs(t, err, ErrStaleVersion)
+	assert.EqualError(t, err, "stale version: users.version is not 3")
+
+	var staleErr *StaleVersionError
+	require.True(t, errors.As(err, &staleErr))
+	assert.Equal(t, "version", staleErr.Column)
+
+	require.NoError(t, Update("users").Set("name", "moe").CheckVersion(0))
+}
+
+func TestDeleteWithVersion(t *testing.T) {
+	t.Parallel()
+	b := Delete("users").Where("id = ?", 1).WithVersion("version", 3)
+
+	sql, args, err := b.ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? AND version = ?", sql)
+	assert.Equal(t, []any{1, 3}, args)
+
+	require.NoError(t, b.CheckVersion(1))
+	require.ErrorIs(t, b.CheckVersion(0), ErrStaleVersion)
+}
+
+func TestWithVersionFullTable(t *testing.T) {
+	t.Parallel()
+	_, _, err := Update("users").Set("name", "moe").WithVersion("version", 3).ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+
+	_, _, err = Delete("users").WithVersion("version", 3).ToSql()
+	require.ErrorIs(t, err, ErrFullTable)
+
+	sql, _, err := Update("users").Set("name", "moe").WithVersion("version", 3).AllowFullTable().ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, version = version + 1 WHERE version = ?", sql)
+}
+
+func TestWithVersionSetExplicitly(t *testing.T) {
+	t.Parallel()
+	_, _, err := Update("t").Set("a", 1).Where("id = ?", 1).WithVersion("v", 1).
+		SetStruct(struct {
+			V int `db:"v"`
+		}{1}).
+		ToSql()
+	require.EqualError(t, err, "version column v is incremented by WithVersion and cannot be set")
+
+	_, _, err = Update("t").SetMap(map[string]any{"v": 2}).Where("id = ?", 1).WithVersion("v", 1).ToSql()
+	require.EqualError(t, err, "version column v is incremented by WithVersion and cannot be set")
+}